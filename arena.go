@@ -0,0 +1,102 @@
+package djson
+
+const (
+	// minSlab and maxSlab are the bounds for the number of array elements in
+	// each slab. The size of the slabs doubles as the decoding progresses,
+	// in order to not waste memory on small payloads.
+	minSlab = 16
+	maxSlab = 1024
+	// localMembers is the number of object members that are collected in a
+	// buffer on the goroutine stack, before the stacks of the Decoder are
+	// used. It covers most objects without touching the heap, even when the
+	// Decoder is not reused.
+	localMembers = 16
+)
+
+// member is an object member that is collected in arena mode.
+type member struct {
+	k string
+	v interface{}
+}
+
+// Arena makes the Decoder allocate memory in bulk, in order to reduce the
+// number of allocations and the total number of bytes that are allocated
+// for arrays and objects:
+//
+//   - array elements are collected in a reusable stack, and the backing
+//     store of each array is carved out of a shared slab with its exact
+//     length, instead of growing it by append.
+//   - object members are collected in a local buffer(and in reusable
+//     stacks for big objects), and each object is created with the exact
+//     number of its members, instead of growing it as they are added.
+//
+// Note that the arrays share their slab, so as long as one of them is kept
+// around, the garbage collector can't release the whole slab. Like with
+// AllocString, you want to use this method only when the decoded values are
+// used as a whole, and they are not extended(appending to an array that
+// was carved from a slab allocates a new backing store).
+// The free part of the slab and the stacks are kept when the Decoder is
+// reused with Reset.
+func (d *Decoder) Arena() {
+	d.arena = true
+}
+
+// carve moves the elements above base in the stack to a new array, that is
+// carved out of the slab.
+func (d *Decoder) carve(base int) []interface{} {
+	elems := d.stack[base:]
+	n := len(elems)
+	var array []interface{}
+	switch {
+	case n == 0:
+		array = make([]interface{}, 0)
+	case n > maxSlab/4:
+		// big arrays get their own backing store, in order to not waste
+		// the rest of the slab.
+		array = make([]interface{}, n)
+	default:
+		if n > len(d.slab) {
+			switch {
+			case d.slabSize == 0:
+				d.slabSize = minSlab
+			case d.slabSize < maxSlab:
+				d.slabSize *= 2
+			}
+			for d.slabSize < n {
+				d.slabSize *= 2
+			}
+			d.slab = make([]interface{}, d.slabSize)
+		}
+		array = d.slab[:n:n]
+		d.slab = d.slab[n:]
+	}
+	copy(array, elems)
+	// release the references to the elements.
+	for i := range elems {
+		elems[i] = nil
+	}
+	d.stack = d.stack[:base]
+	return array
+}
+
+// gather creates a map with the exact number of the collected members of an
+// object; the members in the local buffer, followed by the members above
+// base in the stacks. The members are added in their order, so the last
+// occurrence of a duplicate key wins.
+func (d *Decoder) gather(local []member, base int) map[string]interface{} {
+	elems := d.stack[base:]
+	kbase := len(d.keystack) - len(elems)
+	keys := d.keystack[kbase:]
+	obj := make(map[string]interface{}, len(local)+len(elems))
+	for _, m := range local {
+		obj[m.k] = m.v
+	}
+	for i, k := range keys {
+		obj[k] = elems[i]
+		// release the references to the members.
+		elems[i], keys[i] = nil, ""
+	}
+	d.stack = d.stack[:base]
+	d.keystack = d.keystack[:kbase]
+	return obj
+}
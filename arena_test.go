@@ -0,0 +1,136 @@
+package djson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestArena(t *testing.T) {
+	big := "[" + strings.Repeat("1,", maxSlab) + "1]"
+	d := NewDecoder(nil)
+	d.Arena()
+	for i, in := range []string{
+		string(allValueIndent),
+		`[]`,
+		`[[], [[]], {}]`,
+		`[1, [2, [3, [4]], 5], {"a": [6, 7], "b": {"c": [], "d": [8]}}, 9]`,
+		`{"a": "}", "b": "\"]", "c": [{"d": 1}, {"e": 2}], "f": {}}`,
+		big,
+		`[` + big + `, ` + big + `]`,
+	} {
+		expected, err := Decode([]byte(in))
+		if err != nil {
+			t.Fatalf("#%d: expecting decode not to fail: %q", i, err)
+		}
+		d.Reset([]byte(in))
+		out, err := d.Decode()
+		if err != nil {
+			t.Fatalf("#%d: expecting arena decode not to fail: %q", i, err)
+		}
+		if !reflect.DeepEqual(out, expected) {
+			t.Errorf("#%d: compare to Decode \n\tactual: %v\n\twant: %v", i, out, expected)
+		}
+		if len(d.stack) != 0 {
+			t.Errorf("#%d: expecting the stack to be empty, got: %d", i, len(d.stack))
+		}
+	}
+
+	// the stack is left empty on errors.
+	for _, in := range []string{`[1, [2, 3`, `[1, [2, x]]`, `{"a": [1, 2}`} {
+		d.Reset([]byte(in))
+		if _, err := d.Decode(); err == nil {
+			t.Errorf("expecting %s to fail", in)
+		}
+		if len(d.stack) != 0 {
+			t.Errorf("expecting the stack to be empty, got: %d", len(d.stack))
+		}
+	}
+
+	// arrays are carved with their exact length from the same slab.
+	d.Reset([]byte(`[[1, 2], [3]]`))
+	out, _ := d.DecodeArray()
+	a, b := out[0].([]interface{}), out[1].([]interface{})
+	if cap(a) != 2 || cap(b) != 1 || cap(out) != 2 {
+		t.Errorf("capacities = %d, %d, %d; want 2, 1, 2", cap(a), cap(b), cap(out))
+	}
+	if uintptr(unsafe.Pointer(&b[0]))-uintptr(unsafe.Pointer(&a[0])) != 2*unsafe.Sizeof(b[0]) {
+		t.Error("expecting the arrays to be carved from the same slab")
+	}
+	// appending to a carved array does not override its neighbor.
+	_ = append(a, 4.0)
+	if b[0] != 3.0 {
+		t.Errorf("%v, want 3", b[0])
+	}
+
+	// lenient mode
+	d.Reset([]byte(`[1, [2, x, 3], 4}`))
+	val, errs := d.DecodeLenient()
+	if expected := []interface{}{1.0, []interface{}{2.0, 3.0}, 4.0}; !reflect.DeepEqual(val, expected) {
+		t.Errorf("%v, want %v", val, expected)
+	}
+	if len(errs) != 2 {
+		t.Errorf("expecting 2 errors, got: %v", errs)
+	}
+}
+
+func TestArenaMembers(t *testing.T) {
+	d := NewDecoder(nil)
+	d.Arena()
+	for _, tt := range []struct {
+		in  string
+		out map[string]interface{}
+	}{
+		{`{"a": 1}`, map[string]interface{}{"a": 1.0}},
+		{`{"a": 1, "b": {"c": {}, "d": [{"e": 2}]}, "a": 3}`, map[string]interface{}{"a": 3.0, "b": map[string]interface{}{"c": map[string]interface{}{}, "d": []interface{}{map[string]interface{}{"e": 2.0}}}}},
+		{`{"a,": "}", "b\",": "]"}`, map[string]interface{}{"a,": "}", "b\",": "]"}},
+	} {
+		d.Reset([]byte(tt.in))
+		out, err := d.DecodeObject()
+		if err != nil || !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("DecodeObject(%s) = %v, %v; want %v", tt.in, out, err, tt.out)
+		}
+		if len(d.stack) != 0 || len(d.keystack) != 0 {
+			t.Errorf("expecting the stacks to be empty, got: %d, %d", len(d.stack), len(d.keystack))
+		}
+	}
+
+	// members that do not fit in the local buffer are collected in the
+	// stacks, and nested objects do not override them.
+	var b strings.Builder
+	b.WriteString(`{"k0": {"a": 1}`)
+	for i := 1; i < 3*localMembers; i++ {
+		fmt.Fprintf(&b, `, "k%d": {"a": %d, "b": [%d]}`, i%(2*localMembers), i, i)
+	}
+	b.WriteString(`}`)
+	expected, _ := Decode([]byte(b.String()))
+	d.Reset([]byte(b.String()))
+	if out, err := d.Decode(); err != nil || !reflect.DeepEqual(out, expected) {
+		t.Errorf("Decode(%s) = %v, %v; want %v", b.String(), out, err, expected)
+	}
+	if len(d.stack) != 0 || len(d.keystack) != 0 {
+		t.Errorf("expecting the stacks to be empty, got: %d, %d", len(d.stack), len(d.keystack))
+	}
+
+	// the stacks are left empty on errors, and the valid members are kept
+	// in lenient mode.
+	for _, in := range []string{`{"a": {"b": 1, "c": [2}}`, `{"a": 1, "b": x}`} {
+		d.Reset([]byte(in))
+		if _, err := d.Decode(); err == nil {
+			t.Errorf("expecting %s to fail", in)
+		}
+		if len(d.stack) != 0 || len(d.keystack) != 0 {
+			t.Errorf("expecting the stacks to be empty, got: %d, %d", len(d.stack), len(d.keystack))
+		}
+	}
+	d.Reset([]byte(`{"a": 1, "b": x, "c": {"d": y, "e": 2}}`))
+	val, errs := d.DecodeLenient()
+	if expected := map[string]interface{}{"a": 1.0, "c": map[string]interface{}{"e": 2.0}}; !reflect.DeepEqual(val, expected) {
+		t.Errorf("%v, want %v", val, expected)
+	}
+	if len(errs) != 2 || len(d.stack) != 0 || len(d.keystack) != 0 {
+		t.Errorf("expecting 2 errors and empty stacks, got: %v, %d, %d", errs, len(d.stack), len(d.keystack))
+	}
+}
@@ -187,6 +187,196 @@ func BenchmarkDJsonAllocString(b *testing.B) {
 	})
 }
 
+func BenchmarkDJsonZeroCopy(b *testing.B) {
+	b.Run("small", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dec := djson.NewDecoder(smallFixture)
+			dec.ZeroCopy()
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("medium", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dec := djson.NewDecoder(mediumFixture)
+			dec.ZeroCopy()
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("large", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dec := djson.NewDecoder(largeFixture)
+			dec.ZeroCopy()
+			dec.DecodeObject()
+		}
+	})
+}
+
+func BenchmarkDJsonArena(b *testing.B) {
+	b.Run("small", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dec := djson.NewDecoder(smallFixture)
+			dec.Arena()
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("medium", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dec := djson.NewDecoder(mediumFixture)
+			dec.Arena()
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("large", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dec := djson.NewDecoder(largeFixture)
+			dec.Arena()
+			dec.DecodeObject()
+		}
+	})
+}
+
+func BenchmarkDJsonReset(b *testing.B) {
+	b.Run("small", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		for i := 0; i < b.N; i++ {
+			dec.Reset(smallFixture)
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("medium", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		for i := 0; i < b.N; i++ {
+			dec.Reset(mediumFixture)
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("large", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		for i := 0; i < b.N; i++ {
+			dec.Reset(largeFixture)
+			dec.DecodeObject()
+		}
+	})
+}
+
+func BenchmarkDJsonResetInternKeys(b *testing.B) {
+	b.Run("small", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		dec.InternKeys(1024)
+		for i := 0; i < b.N; i++ {
+			dec.Reset(smallFixture)
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("medium", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		dec.InternKeys(1024)
+		for i := 0; i < b.N; i++ {
+			dec.Reset(mediumFixture)
+			dec.DecodeObject()
+		}
+	})
+
+	b.Run("large", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		dec.InternKeys(1024)
+		for i := 0; i < b.N; i++ {
+			dec.Reset(largeFixture)
+			dec.DecodeObject()
+		}
+	})
+}
+
+// BenchmarkDJsonThroughput reports the decoding throughput(MB/s) of the
+// large payload and of a string-heavy payload.
+func BenchmarkDJsonThroughput(b *testing.B) {
+	b.Run("large", func(b *testing.B) {
+		b.SetBytes(int64(len(largeFixture)))
+		for i := 0; i < b.N; i++ {
+			djson.DecodeObject(largeFixture)
+		}
+	})
+
+	b.Run("strings", func(b *testing.B) {
+		b.SetBytes(int64(len(stringFixture)))
+		for i := 0; i < b.N; i++ {
+			djson.DecodeArray(stringFixture)
+		}
+	})
+}
+
+func BenchmarkDJsonStructuralIndex(b *testing.B) {
+	b.Run("recursive", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		b.SetBytes(int64(len(eventsFixture)))
+		for i := 0; i < b.N; i++ {
+			dec.Reset(eventsFixture)
+			dec.DecodeArray()
+		}
+	})
+
+	b.Run("index", func(b *testing.B) {
+		dec := djson.NewDecoder(nil)
+		dec.StructuralIndex()
+		b.SetBytes(int64(len(eventsFixture)))
+		for i := 0; i < b.N; i++ {
+			dec.Reset(eventsFixture)
+			dec.DecodeArray()
+		}
+	})
+}
+
+func BenchmarkDJsonDecodeArrayParallel(b *testing.B) {
+	b.Run("sequential", func(b *testing.B) {
+		b.SetBytes(int64(len(eventsFixture)))
+		for i := 0; i < b.N; i++ {
+			djson.DecodeArray(eventsFixture)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		b.SetBytes(int64(len(eventsFixture)))
+		for i := 0; i < b.N; i++ {
+			djson.DecodeArrayParallel(eventsFixture, 0)
+		}
+	})
+}
+
+func BenchmarkDJsonEachPath(b *testing.B) {
+	paths := [][]string{
+		{"person", "name", "fullName"},
+		{"person", "github", "followers"},
+		{"company"},
+	}
+	b.Run("decode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			djson.DecodeObject(mediumFixture)
+		}
+	})
+	b.Run("each", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			djson.EachPath(mediumFixture, paths, func(int, interface{}, djson.ValueType) error {
+				return nil
+			})
+		}
+	})
+}
+
+func BenchmarkDJsonLazy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		doc, _ := djson.DecodeLazy(mediumFixture)
+		doc.Get("person").Get("name").Get("fullName").Value()
+		doc.Get("person").Get("github").Get("followers").Value()
+		doc.Get("company").Value()
+	}
+}
+
 /*
 // This is not part of the benchmark test cases;
 // Trying to show the preformence when translating the jsonparser's
@@ -24,7 +24,9 @@ func quoteChar(c byte) string {
 	return "'" + s[1:len(s)-1] + "'"
 }
 
-func unquoteBytes(s, b []byte) (t []byte, ok bool) {
+// unquoteBytes unquotes the content of a JSON string. If raw is true, invalid
+// UTF-8 sequences are kept as is instead of being coerced to U+FFFD.
+func unquoteBytes(s, b []byte, raw bool) (t []byte, ok bool) {
 	if len(s) == 0 {
 		return t, true
 	}
@@ -128,6 +130,12 @@ func unquoteBytes(s, b []byte) (t []byte, ok bool) {
 			r++
 			w++
 
+		// Keep the raw bytes.
+		case raw:
+			b[w] = c
+			r++
+			w++
+
 		// Coerce to well-formed UTF-8.
 		default:
 			rr, size := utf8.DecodeRune(s[r:])
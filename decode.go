@@ -1,8 +1,11 @@
 package djson
 
 import (
+	"bytes"
+	"math"
 	"strconv"
-	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // Decoder is the object that holds the state of the decoding
@@ -12,8 +15,50 @@ type Decoder struct {
 	data      []byte
 	sdata     string
 	usestring bool
+	zerocopy  bool
+	lenient   bool
+	errs      []*SyntaxError
+	comments  bool
+	err       error
+	json5     bool
+	trailing  bool
+	nan       bool
+	utf8      UTF8Policy
+	keys      map[string]string
+	maxKeys   int
+	scratch   []byte
+	arena     bool
+	slab      []interface{}
+	slabSize  int
+	stack     []interface{}
+	keystack  []string
+	indexed   bool
+	idx       []uint32
+	sizes     []uint32
+	open      []uint32
+	cur       int
+	nsize     int
+	proj      projection
 }
 
+// UTF8Policy defines how the Decoder handles invalid UTF-8 in strings.
+type UTF8Policy int
+
+const (
+	// UTF8Replace replaces each invalid UTF-8 byte with the U+FFFD
+	// replacement character, like encoding/json does. This is the default.
+	UTF8Replace UTF8Policy = iota
+
+	// UTF8Reject rejects strings that contain invalid UTF-8 with a
+	// SyntaxError that points at the first bad byte. Lone UTF-16 surrogates
+	// in \u escapes are rejected as well.
+	UTF8Reject
+
+	// UTF8Pass keeps invalid UTF-8 bytes as is in the decoded strings. Lone
+	// UTF-16 surrogates in \u escapes are still replaced with U+FFFD.
+	UTF8Pass
+)
+
 // NewDecoder creates new Decoder from the JSON-encoded data
 func NewDecoder(data []byte) *Decoder {
 	return &Decoder{
@@ -22,6 +67,54 @@ func NewDecoder(data []byte) *Decoder {
 	}
 }
 
+// ZeroCopy makes the Decoder return strings that are views into the data it
+// decodes, instead of copies. It is the same as AllocString, but it does not
+// copy the data even once, and therefore it uses the "unsafe" package.
+// Strings that contain escape sequences or invalid UTF-8 are still
+// allocated, and so are non-ASCII strings in JSON5 mode.
+//
+// It is only safe to use this method for read-only workloads that control
+// the lifetime of the data; The data must not be modified or reused(e.g. by
+// returning it to a sync.Pool) as long as the decoded values are used,
+// because doing so changes the strings that were already returned, and
+// breaks the immutability guarantee of Go strings. Also, like AllocString,
+// any string that is kept around prevents the whole data from being garbage
+// collected.
+//
+// When the package is built with the "purego" build tag, or with a Go version
+// older than 1.20, ZeroCopy falls back to AllocString.
+func (d *Decoder) ZeroCopy() {
+	d.sdata = unsafeString(d.data)
+	d.usestring = true
+	d.zerocopy = true
+}
+
+// Reset resets the Decoder to decode the given JSON-encoded data, so it can
+// be reused for multiple payloads(e.g. the records of an NDJSON stream)
+// instead of creating a new one for each of them.
+// The options that were set on the Decoder are kept, and so are its internal
+// buffers, such as the intern table of InternKeys and the scratch space that
+// is used for unescaping strings.
+//
+// Note that if AllocString was called, the string version of the new data
+// is allocated again, because the strings that were returned from the
+// previous decoding still reference the old one. With ZeroCopy, the new data
+// is referenced instead.
+func (d *Decoder) Reset(data []byte) {
+	d.data = data
+	d.pos = 0
+	d.end = len(data)
+	d.err = nil
+	d.errs = nil
+	d.lenient = false
+	d.sdata = ""
+	if d.zerocopy {
+		d.sdata = unsafeString(data)
+	} else if d.usestring {
+		d.sdata = string(data)
+	}
+}
+
 // AllocString pre-allocates a string version of the data before starting
 // to decode the data.
 // It is used to make the decode operation faster(see below) by doing one
@@ -63,6 +156,75 @@ func (d *Decoder) AllocString() {
 	d.usestring = true
 }
 
+// AllowComments makes the Decoder treat JavaScript-style comments(JSONC) as
+// white spaces. Both line comments(`// ...`) that end with a newline or with
+// the end of the input, and block comments(`/* ... */`) are supported.
+// An unterminated block comment is reported as a SyntaxError that points
+// to the beginning of the comment.
+//
+//	dec := djson.NewDecoder([]byte(`{
+//		// the server port
+//		"port": 8080 /* default */
+//	}`))
+//	dec.AllowComments()
+//	val, err := dec.DecodeObject()
+func (d *Decoder) AllowComments() {
+	d.comments = true
+}
+
+// AllowTrailingCommas makes the Decoder accept a comma after the last element
+// of an array or the last member of an object, e.g. `[1,2,]` or `{"a":1,}`.
+// Note that empty elements(e.g. `[1,,2]` or `[,]`) are still rejected.
+func (d *Decoder) AllowTrailingCommas() {
+	d.trailing = true
+}
+
+// AllowNaN makes the Decoder accept the NaN, Infinity and -Infinity literals
+// that are emitted by encoders such as Python's json.dumps, and decode them
+// to math.NaN() and math.Inf(±1) respectively. See Encoder.AllowNaN for
+// writing them back.
+func (d *Decoder) AllowNaN() {
+	d.nan = true
+}
+
+// Strict makes the Decoder conform to RFC 8259, by disabling all the
+// extensions that were enabled before it was called(e.g. AllowComments,
+// AllowTrailingCommas, AllowNaN and JSON5). Any input that is not valid
+// JSON is rejected with an error, including invalid UTF-8 and lone UTF-16
+// surrogates in strings. See UTF8Reject.
+//
+// Note that the behavior for inputs that RFC 8259 leaves up to the
+// implementation(e.g. numbers that are out of the float64 range) is
+// documented in the JSONTestSuite tests of this package.
+func (d *Decoder) Strict() {
+	d.utf8 = UTF8Reject
+	d.json5 = false
+	d.comments = false
+	d.trailing = false
+	d.nan = false
+}
+
+// InvalidUTF8 sets the policy for handling invalid UTF-8 in strings.
+// See UTF8Policy for more details.
+func (d *Decoder) InvalidUTF8(p UTF8Policy) {
+	d.utf8 = p
+}
+
+// InternKeys enables an intern table for object keys, that holds up to max
+// distinct keys. Keys that are already in the table are returned without
+// allocating a new string, which cuts most of the key allocations when the
+// same keys appear in every record(e.g. an array of events, or an NDJSON
+// stream). Once the table is full, new keys are allocated as usual.
+// The table is kept when the Decoder is reused with Reset.
+//
+// The intern table is used for standard JSON keys, and not for JSON5 keys.
+func (d *Decoder) InternKeys(max int) {
+	if d.keys == nil {
+		d.keys = make(map[string]string)
+	}
+	d.maxKeys = max
+}
+
 // Decode parses the JSON-encoded data and returns an interface value.
 // The interface value could be one of these:
 //
@@ -80,6 +242,11 @@ func (d *Decoder) AllocString() {
 //	err := json.Unmarshal(data, &v)
 //
 func (d *Decoder) Decode() (interface{}, error) {
+	if d.useIndex() {
+		if val, ok := d.decodeIndexed(); ok {
+			return val, nil
+		}
+	}
 	val, err := d.any()
 	if err != nil {
 		return nil, err
@@ -93,6 +260,14 @@ func (d *Decoder) Decode() (interface{}, error) {
 // DecodeObject is the same as Decode but it returns map[string]interface{}.
 // You should use it to parse JSON objects.
 func (d *Decoder) DecodeObject() (map[string]interface{}, error) {
+	if d.useIndex() {
+		if val, ok := d.decodeIndexed(); ok {
+			if obj, ok := val.(map[string]interface{}); ok {
+				return obj, nil
+			}
+			d.pos = 0
+		}
+	}
 	if c := d.skipSpaces(); c != '{' {
 		return nil, d.error(c, "looking for beginning of object")
 	}
@@ -109,6 +284,14 @@ func (d *Decoder) DecodeObject() (map[string]interface{}, error) {
 // DecodeArray is the same as Decode but it returns []interface{}.
 // You should use it to parse JSON arrays.
 func (d *Decoder) DecodeArray() ([]interface{}, error) {
+	if d.useIndex() {
+		if val, ok := d.decodeIndexed(); ok {
+			if array, ok := val.([]interface{}); ok {
+				return array, nil
+			}
+			d.pos = 0
+		}
+	}
 	if c := d.skipSpaces(); c != '[' {
 		return nil, d.error(c, "looking for beginning of array")
 	}
@@ -127,12 +310,23 @@ func (d *Decoder) DecodeArray() ([]interface{}, error) {
 func (d *Decoder) any() (interface{}, error) {
 	switch c := d.skipSpaces(); c {
 	case '"':
+		if d.json5 {
+			return d.string5(c)
+		}
 		return d.string()
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		if d.json5 {
+			return d.number5()
+		}
 		return d.number()
 	case '-':
-		d.pos++
-		if c = d.data[d.pos]; c < '0' && c > '9' {
+		if d.json5 {
+			return d.number5()
+		}
+		if c = d.next(); d.nan && c == 'I' {
+			return d.nonFinite(true)
+		}
+		if c < '0' || c > '9' {
 			return nil, d.error(c, "in negative numeric literal")
 		}
 		n, err := d.number()
@@ -174,6 +368,19 @@ func (d *Decoder) any() (interface{}, error) {
 		return d.array()
 	case '{':
 		return d.object()
+	case '\'':
+		if d.json5 {
+			return d.string5(c)
+		}
+		return nil, d.error(c, "looking for beginning of value")
+	case '+', '.', 'I', 'N':
+		if d.json5 {
+			return d.number5()
+		}
+		if d.nan && c != '+' && c != '.' {
+			return d.nonFinite(false)
+		}
+		return nil, d.error(c, "looking for beginning of value")
 	default:
 		return nil, d.error(c, "looking for beginning of value")
 	}
@@ -181,80 +388,167 @@ func (d *Decoder) any() (interface{}, error) {
 
 // string called by `any` or `object`(for map keys) after reading `"`
 func (d *Decoder) string() (string, error) {
-	d.pos++
+	start := d.pos + 1
+	unquote, err := d.scanString()
+	if err != nil {
+		return "", err
+	}
+	if !unquote {
+		return d.slice(start, d.pos-1), nil
+	}
+	// valid UTF-8 strings without escape sequences are sliced as well, when
+	// there is a string version of the data.
+	if raw := d.data[start : d.pos-1]; d.usestring && bytes.IndexByte(raw, '\\') < 0 && utf8.Valid(raw) {
+		return d.slice(start, d.pos-1), nil
+	}
+	// stack-allocated array for allocation-free unescaping of small strings
+	// if a string longer than this needs to be escaped, it will result in a
+	// heap allocation; idea comes from github.com/burger/jsonparser
+	var stackbuf [64]byte
+	data, ok := d.unquote(d.data[start:d.pos-1], stackbuf[:])
+	if !ok {
+		return "", ErrStringEscape
+	}
+	return string(data), nil
+}
 
-	var (
-		unquote bool
-		start   = d.pos
-	)
+// key called by `object` after reading `"`. It is the same as `string`, but
+// it uses the intern table if it is enabled.
+func (d *Decoder) key() (string, error) {
+	if d.keys == nil {
+		return d.string()
+	}
+	start := d.pos + 1
+	unquote, err := d.scanString()
+	if err != nil {
+		return "", err
+	}
+	data := d.data[start : d.pos-1]
+	if unquote {
+		var (
+			ok       bool
+			stackbuf [64]byte
+		)
+		if data, ok = d.unquote(data, stackbuf[:]); !ok {
+			return "", ErrStringEscape
+		}
+	}
+	return d.intern(data), nil
+}
+
+// intern returns the interned string of the given key, and adds it to the
+// intern table if it is not full.
+func (d *Decoder) intern(data []byte) string {
+	// the compiler does not allocate for the string conversion in lookups.
+	if k, ok := d.keys[string(data)]; ok {
+		return k
+	}
+	k := string(data)
+	if len(d.keys) < d.maxKeys {
+		d.keys[k] = k
+	}
+	return k
+}
+
+// unquote unquotes the content of a string into the given stack buffer, or
+// into the scratch space of the Decoder if the string is bigger than it.
+// The scratch space is reused between strings, and across Reset.
+func (d *Decoder) unquote(s, stackbuf []byte) ([]byte, bool) {
+	b := stackbuf
+	if len(s) > len(b) {
+		if cap(d.scratch) < len(s)+2*utf8.UTFMax {
+			d.scratch = make([]byte, len(s)+2*utf8.UTFMax)
+		}
+		b = d.scratch[:cap(d.scratch)]
+	}
+	return unquoteBytes(s, b, d.utf8 == UTF8Pass)
+}
+
+// scanString validates the string that starts at the current position, and
+// moves to the position after its closing quote. It reports whether the
+// string needs to be unquoted(i.e. it contains escapes or non-ASCII bytes).
+func (d *Decoder) scanString() (unquote bool, err error) {
+	d.pos++
 
 scan:
 	for {
+		// skip the plain ASCII bytes a word at a time.
+		d.pos += plainString(d.data[d.pos:d.end])
 		if d.pos >= d.end {
-			return "", ErrUnexpectedEOF
+			return false, ErrUnexpectedEOF
 		}
 
 		c := d.data[d.pos]
 		switch {
 		case c == '"':
-			var s string
-			if unquote {
-				// stack-allocated array for allocation-free unescaping of small strings
-				// if a string longer than this needs to be escaped, it will result in a
-				// heap allocation; idea comes from github.com/burger/jsonparser
-				var stackbuf [64]byte
-				data, ok := unquoteBytes(d.data[start:d.pos], stackbuf[:])
-				if !ok {
-					return "", ErrStringEscape
-				}
-				s = string(data)
-			} else {
-				if d.usestring {
-					s = d.sdata[start:d.pos]
-				} else {
-
-					s = string(d.data[start:d.pos])
-				}
-			}
 			d.pos++
-			return s, nil
+			return unquote, nil
 		case c == '\\':
 			d.pos++
 			unquote = true
+			if d.pos >= d.end {
+				return false, ErrUnexpectedEOF
+			}
 			switch c := d.data[d.pos]; c {
 			case 'u':
 				goto escape_u
 			case 'b', 'f', 'n', 'r', 't', '\\', '/', '"':
 				d.pos++
 			default:
-				return "", d.error(c, "in string escape code")
+				return false, d.error(c, "in string escape code")
 			}
 		case c < 0x20:
-			return "", d.error(c, "in string literal")
-		default:
+			return false, d.error(c, "in string literal")
+		case d.utf8 == UTF8Replace:
 			d.pos++
-			if c > unicode.MaxASCII {
-				unquote = true
+			unquote = true
+		case d.utf8 == UTF8Reject:
+			r, size := utf8.DecodeRune(d.data[d.pos:d.end])
+			if r == utf8.RuneError && size == 1 {
+				return false, &SyntaxError{"invalid UTF-8 in string literal", d.pos + 1}
 			}
+			d.pos += size
+		default:
+			d.pos++
 		}
 	}
 
 escape_u:
 	d.pos++
-	for i := 0; i < 3; i++ {
-		if d.pos < d.end {
-			c := d.data[d.pos+i]
-			if '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F' {
-				continue
-			}
-			return "", d.error(c, "in \\u hexadecimal character escape")
+	for i := 0; i < 4; i++ {
+		if d.pos >= d.end {
+			return false, ErrInvalidHexEscape
+		}
+		if c := d.data[d.pos]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false, d.error(c, "in \\u hexadecimal character escape")
+		}
+		d.pos++
+	}
+	if d.utf8 == UTF8Reject {
+		if err := d.surrogate(); err != nil {
+			return false, err
 		}
-		return "", ErrInvalidHexEscape
 	}
-	d.pos += 3
 	goto scan
 }
 
+// surrogate called by `scanString` after reading a \u escape, and returns an
+// error if it is a lone UTF-16 surrogate. A valid surrogate pair is consumed.
+func (d *Decoder) surrogate() error {
+	start := d.pos - 6
+	r := getu4(d.data[start:d.pos])
+	if !utf16.IsSurrogate(r) {
+		return nil
+	}
+	if r < 0xDC00 {
+		if r1 := getu4(d.data[d.pos:d.end]); r1 >= 0xDC00 && r1 < 0xE000 {
+			d.pos += 6
+			return nil
+		}
+	}
+	return &SyntaxError{"invalid lone surrogate " + string(d.data[start:d.pos]) + " in string literal", start + 1}
+}
+
 // number called by `any` after reading number between 0 to 9
 func (d *Decoder) number() (float64, error) {
 	var (
@@ -276,13 +570,11 @@ func (d *Decoder) number() (float64, error) {
 
 	// . followed by 1 or more digits
 	if c == '.' {
-		d.pos++
 		isFloat = true
-		if c = d.data[d.pos]; c < '0' && c > '9' {
+		if c = d.next(); c < '0' || c > '9' {
 			return 0, d.error(c, "after decimal point in numeric literal")
 		}
-		for c = d.next(); '0' <= c && c <= '9'; {
-			c = d.next()
+		for ; '0' <= c && c <= '9'; c = d.next() {
 		}
 	}
 
@@ -291,16 +583,21 @@ func (d *Decoder) number() (float64, error) {
 	if c == 'e' || c == 'E' {
 		isFloat = true
 		if c = d.next(); c == '+' || c == '-' {
-			if c = d.next(); c < '0' || c > '9' {
-				return 0, d.error(c, "in exponent of numeric literal")
-			}
-		}
-		for c = d.next(); '0' <= c && c <= '9'; {
 			c = d.next()
 		}
+		if c < '0' || c > '9' {
+			return 0, d.error(c, "in exponent of numeric literal")
+		}
+		for ; '0' <= c && c <= '9'; c = d.next() {
+		}
 	}
 
-	if isFloat {
+	// integers with more than 15 digits may lose their precision, and they
+	// need to be rounded like floats.
+	if isFloat || d.pos-start > 15 {
+		if f, ok := parseFloat(d.data[start:d.pos]); ok {
+			return f, nil
+		}
 		var (
 			err error
 			sn  string
@@ -317,6 +614,39 @@ func (d *Decoder) number() (float64, error) {
 	return n, nil
 }
 
+// nonFinite called by `any` or `number5` to read the Infinity or NaN literals
+// that follow the optional sign of the number.
+func (d *Decoder) nonFinite(neg bool) (float64, error) {
+	if d.data[d.pos] == 'N' {
+		if err := d.literal("NaN", "in literal NaN"); err != nil {
+			return 0, err
+		}
+		return math.NaN(), nil
+	}
+	if err := d.literal("Infinity", "in literal Infinity"); err != nil {
+		return 0, err
+	}
+	if neg {
+		return math.Inf(-1), nil
+	}
+	return math.Inf(1), nil
+}
+
+// literal consumes the given literal from the current position, or returns
+// an error that points to the first character that does not match.
+func (d *Decoder) literal(lit, context string) error {
+	for i := 0; i < len(lit); i++ {
+		if d.pos >= d.end {
+			return ErrUnexpectedEOF
+		}
+		if d.data[d.pos] != lit[i] {
+			return d.error(d.data[d.pos], context)
+		}
+		d.pos++
+	}
+	return nil
+}
+
 // array accept valid JSON array value
 func (d *Decoder) array() ([]interface{}, error) {
 	// the '[' token already scanned
@@ -326,8 +656,13 @@ func (d *Decoder) array() ([]interface{}, error) {
 		c     byte
 		v     interface{}
 		err   error
-		array = make([]interface{}, 0)
+		start int
+		array []interface{}
+		base  = len(d.stack)
 	)
+	if !d.arena {
+		array = make([]interface{}, 0)
+	}
 
 	// look ahead for ] - if the array is empty.
 	if c = d.skipSpaces(); c == ']' {
@@ -336,24 +671,51 @@ func (d *Decoder) array() ([]interface{}, error) {
 	}
 
 scan:
+	start = d.pos
 	if v, err = d.any(); err != nil {
-		goto out
+		goto fail
 	}
 
-	array = append(array, v)
+	if d.arena {
+		d.stack = append(d.stack, v)
+	} else {
+		array = append(array, v)
+	}
 
 	// next token must be ',' or ']'
 	if c = d.skipSpaces(); c == ',' {
 		d.pos++
+		if d.trailing && d.skipSpaces() == ']' {
+			d.pos++
+			goto out
+		}
 		goto scan
 	} else if c == ']' {
 		d.pos++
 	} else {
+		start = d.pos
 		err = d.error(c, "after array element")
+		goto fail
 	}
 
 out:
+	if d.arena {
+		array = d.carve(base)
+	}
 	return array, err
+
+fail:
+	if !d.lenient {
+		goto out
+	}
+	d.report(err)
+	if c, err = d.resync(start), nil; c == ',' {
+		d.pos++
+		goto scan
+	} else if c != 0 {
+		d.pos++
+	}
+	goto out
 }
 
 // object accept valid JSON array value
@@ -362,59 +724,137 @@ func (d *Decoder) object() (map[string]interface{}, error) {
 	d.pos++
 
 	var (
-		c   byte
-		k   string
-		v   interface{}
-		err error
-		obj = make(map[string]interface{})
+		c     byte
+		k     string
+		v     interface{}
+		err   error
+		start int
+		keep  bool
+		obj   map[string]interface{}
+		proj  = d.proj
+		// in arena mode, the members are collected in a local buffer, and
+		// in the stacks once it is full. The map is created with their
+		// exact number when the object is closed.
+		collect = d.arena && proj == nil
+		local   [localMembers]member
+		n       int
+		base    = len(d.stack)
 	)
+	switch {
+	case proj != nil:
+		obj = make(map[string]interface{}, len(proj))
+	case !collect:
+		obj = make(map[string]interface{})
+	}
 
 	// look ahead for } - if the object has no keys.
 	if c = d.skipSpaces(); c == '}' {
 		d.pos++
-		return obj, nil
+		goto out
 	}
 
 	for {
 		// read string key
-		if c = d.skipSpaces(); c != '"' {
+		c = d.skipSpaces()
+		start = d.pos
+		keep = true
+		switch {
+		case c == '"' && !d.json5 && proj != nil:
+			k, keep, err = d.projectedKey()
+		case c == '"' && !d.json5:
+			k, err = d.key()
+		case c == '}' && d.trailing:
+			// trailing comma
+			d.pos++
+			goto out
+		case d.json5:
+			if k, err = d.key5(c); proj != nil {
+				_, keep = proj[k]
+			}
+		default:
 			err = d.error(c, "looking for beginning of object key string")
-			break
 		}
-		if k, err = d.string(); err != nil {
-			break
+		if err != nil {
+			goto fail
 		}
 
 		// read colon before value
 		c = d.skipSpaces()
 		if c != ':' {
 			err = d.error(c, "after object key")
-			break
+			goto fail
 		}
 		d.pos++
 
-		// read and assign value
-		if v, err = d.any(); err != nil {
-			break
+		// read and assign value, or skip it if it is not part of the
+		// projection.
+		if !keep {
+			if err = d.skip(); err != nil {
+				goto fail
+			}
+		} else {
+			if proj != nil {
+				d.proj = proj[k]
+			}
+			v, err = d.any()
+			if d.proj = proj; err != nil {
+				goto fail
+			}
+			switch {
+			case !collect:
+				obj[k] = v
+			case n < len(local):
+				local[n] = member{k, v}
+				n++
+			default:
+				d.stack = append(d.stack, v)
+				d.keystack = append(d.keystack, k)
+			}
 		}
 
-		obj[k] = v
-
 		// next token must be ',' or '}'
 		if c = d.skipSpaces(); c == '}' {
 			d.pos++
 			break
 		} else if c == ',' {
 			d.pos++
+			continue
 		} else {
+			start = d.pos
 			err = d.error(c, "after object key:value pair")
+		}
+
+	fail:
+		if !d.lenient {
 			break
 		}
+		// drop the broken member and continue from the next one, if any.
+		d.report(err)
+		if c, err = d.resync(start), nil; c == ',' {
+			d.pos++
+			continue
+		} else if c != 0 {
+			d.pos++
+		}
+		break
 	}
 
+out:
+	if collect {
+		obj = d.gather(local[:n], base)
+	}
 	return obj, err
 }
 
+// slice returns the string in the given range of the input, without
+// decoding it.
+func (d *Decoder) slice(start, end int) string {
+	if d.usestring {
+		return d.sdata[start:end]
+	}
+	return string(d.data[start:end])
+}
+
 // next return the next byte in the input
 func (d *Decoder) next() byte {
 	d.pos++
@@ -433,14 +873,56 @@ loop:
 	switch c := d.data[d.pos]; c {
 	case ' ', '\t', '\n', '\r':
 		d.pos++
+		// indentation comes in runs; skip them a word at a time.
+		d.pos += whitespace(d.data[d.pos:d.end])
 		goto loop
+	case '/':
+		if d.comments && d.comment() {
+			goto loop
+		}
+		return c
+	case '\v', '\f', 0xc2, 0xe1, 0xe2, 0xe3, 0xef:
+		// lead bytes of the additional JSON5 white spaces. The strict mode
+		// does not pay for the call.
+		if d.json5 && d.space5() {
+			goto loop
+		}
+		return c
 	default:
 		return c
 	}
 }
 
+// comment skips the comment that starts at the current position and reports
+// whether it succeeded. The position is left unchanged if there is no comment
+// or if the block comment is unterminated.
+func (d *Decoder) comment() bool {
+	if d.end-d.pos < 2 {
+		return false
+	}
+	switch d.data[d.pos+1] {
+	case '/':
+		if i := bytes.IndexByte(d.data[d.pos+2:d.end], '\n'); i >= 0 {
+			d.pos += i + 3
+		} else {
+			d.pos = d.end
+		}
+		return true
+	case '*':
+		if i := bytes.Index(d.data[d.pos+2:d.end], []byte("*/")); i >= 0 {
+			d.pos += i + 4
+			return true
+		}
+		d.err = &SyntaxError{"unterminated block comment", d.pos + 1}
+	}
+	return false
+}
+
 // emit sytax errors
 func (d *Decoder) error(c byte, context string) error {
+	if d.err != nil {
+		return d.err
+	}
 	if d.pos < d.end {
 		return &SyntaxError{"invalid character " + quoteChar(c) + " " + context, d.pos + 1}
 	}
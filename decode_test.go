@@ -3,6 +3,7 @@ package djson
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -52,11 +53,25 @@ var decodeTests = []decodeTest{
 	{in: `tru`, err: ErrUnexpectedEOF},
 	{in: `"as`, err: ErrUnexpectedEOF},
 	{in: `[1,`, err: ErrUnexpectedEOF},
+	{in: `-`, err: ErrUnexpectedEOF},
+	{in: `1.`, err: ErrUnexpectedEOF},
+	{in: `1e`, err: ErrUnexpectedEOF},
+	{in: `1e+`, err: ErrUnexpectedEOF},
+	{in: `"\`, err: ErrUnexpectedEOF},
+	{in: `"\u12`, err: ErrInvalidHexEscape},
 
 	// syntax errors
 	{in: `{"X": "foo", "Y"}`, err: &SyntaxError{"invalid character '}' after object key", 17}},
 	{in: `[1, 2, 3+]`, err: &SyntaxError{"invalid character '+' after array element", 9}},
 	{in: `{"X":12x}`, err: &SyntaxError{"invalid character 'x' after object key:value pair", 8}},
+	{in: `-x`, err: &SyntaxError{"invalid character 'x' in negative numeric literal", 2}},
+	{in: `[-]`, err: &SyntaxError{"invalid character ']' in negative numeric literal", 3}},
+	{in: `1.e5`, err: &SyntaxError{"invalid character 'e' after decimal point in numeric literal", 3}},
+	{in: `[1.]`, err: &SyntaxError{"invalid character ']' after decimal point in numeric literal", 4}},
+	{in: `1ex`, err: &SyntaxError{"invalid character 'x' in exponent of numeric literal", 3}},
+	{in: `1e+x`, err: &SyntaxError{"invalid character 'x' in exponent of numeric literal", 4}},
+	{in: `"\u12"`, err: &SyntaxError{"invalid character '\"' in \\u hexadecimal character escape", 6}},
+	{in: `"\u123x"`, err: &SyntaxError{"invalid character 'x' in \\u hexadecimal character escape", 7}},
 
 	// raw value errors
 	{in: "\x01 42", err: &SyntaxError{"invalid character '\\x01' looking for beginning of value", 1}},
@@ -253,3 +268,205 @@ func TestType(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowComments(t *testing.T) {
+	for i, tt := range []decodeTest{
+		{in: "// comment\n1", expected: 1.0},
+		{in: "1 // comment", expected: 1.0},
+		{in: "/* comment */ 1 /**/", expected: 1.0},
+		{in: "/* multi\nline\n*/ true", expected: true},
+		{in: "[1, /* two */ 2 // three\n, 3]", expected: []interface{}{1.0, 2.0, 3.0}},
+		{in: "{\n\t// key\n\t\"a\" /* : */ : \"//not a comment\" /* , */\n}", expected: map[string]interface{}{"a": "//not a comment"}},
+		{in: "/**/1/***/", expected: 1.0},
+		{in: "1 /* unterminated", err: &SyntaxError{"unterminated block comment", 3}},
+		{in: "[1, /* unterminated */ 2, /*", err: &SyntaxError{"unterminated block comment", 27}},
+		{in: "{\"a\": /*/ 1}", err: &SyntaxError{"unterminated block comment", 7}},
+		{in: "1 / 2", err: &SyntaxError{"invalid character '/' after top-level value", 3}},
+		{in: "[1, /", err: &SyntaxError{"invalid character '/' looking for beginning of value", 5}},
+	} {
+		d := NewDecoder([]byte(tt.in))
+		d.AllowComments()
+		out, err := d.Decode()
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: %v, want %v", i, err, tt.err)
+		}
+		if !reflect.DeepEqual(out, tt.expected) {
+			t.Errorf("#%d: %v, want %v", i, out, tt.expected)
+		}
+	}
+
+	// comments are not allowed by default
+	if _, err := Decode([]byte("1 // comment")); err == nil {
+		t.Error("expecting comments to fail without AllowComments")
+	}
+}
+
+func TestAllowTrailingCommas(t *testing.T) {
+	tests := []decodeTest{
+		{in: `[1,2,]`, expected: []interface{}{1.0, 2.0}},
+		{in: "[1 , 2 ,\n]", expected: []interface{}{1.0, 2.0}},
+		{in: `{"a":1,}`, expected: map[string]interface{}{"a": 1.0}},
+		{in: `{"a":[{"b":[],},],}`, expected: map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": []interface{}{}}}}},
+		{in: `[,]`, err: &SyntaxError{"invalid character ',' looking for beginning of value", 2}},
+		{in: `{,}`, err: &SyntaxError{"invalid character ',' looking for beginning of object key string", 2}},
+		{in: `[1,,]`, err: &SyntaxError{"invalid character ',' looking for beginning of value", 4}},
+		{in: `{"a":1,,}`, err: &SyntaxError{"invalid character ',' looking for beginning of object key string", 8}},
+		{in: `[1,]]`, err: &SyntaxError{"invalid character ']' after top-level value", 5}},
+		{in: `{"a":1,]`, err: &SyntaxError{"invalid character ']' looking for beginning of object key string", 8}},
+	}
+	// the option must not change the result of valid or invalid payloads.
+	for i, tt := range append(tests, decodeTests...) {
+		d := NewDecoder([]byte(tt.in))
+		d.AllowTrailingCommas()
+		out, err := d.Decode()
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: %v, want %v", i, err, tt.err)
+		}
+		if out != nil {
+			if !reflect.DeepEqual(out, tt.expected) {
+				t.Errorf("#%d: %v, want %v", i, out, tt.expected)
+			}
+		}
+	}
+
+	// trailing commas are not allowed by default
+	for _, in := range []string{`[1,2,]`, `{"a":1,}`} {
+		if _, err := Decode([]byte(in)); err == nil {
+			t.Errorf("expecting %s to fail without AllowTrailingCommas", in)
+		}
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	for i, tt := range []struct {
+		in     string
+		policy UTF8Policy
+		decodeTest
+	}{
+		// valid input is decoded the same way by all policies
+		{in: `"Déjà vu \u00e9"`, policy: UTF8Replace, decodeTest: decodeTest{expected: "Déjà vu é"}},
+		{in: `"Déjà vu \u00e9"`, policy: UTF8Reject, decodeTest: decodeTest{expected: "Déjà vu é"}},
+		{in: `"Déjà vu \u00e9"`, policy: UTF8Pass, decodeTest: decodeTest{expected: "Déjà vu é"}},
+		{in: `"g-clef: \uD834\uDD1E"`, policy: UTF8Reject, decodeTest: decodeTest{expected: "g-clef: \U0001D11E"}},
+
+		// replace
+		{in: "\"hello\xffworld\"", policy: UTF8Replace, decodeTest: decodeTest{expected: "hello\ufffdworld"}},
+		{in: "\"hello\xc2\xc2\\nworld\"", policy: UTF8Replace, decodeTest: decodeTest{expected: "hello\ufffd\ufffd\nworld"}},
+		{in: "\"hello\\ud800world\"", policy: UTF8Replace, decodeTest: decodeTest{expected: "hello\ufffdworld"}},
+
+		// reject
+		{in: "\"hello\xffworld\"", policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{"invalid UTF-8 in string literal", 7}}},
+		{in: "[\"a\", \"\\n\xed\xa0\x80\"]", policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{"invalid UTF-8 in string literal", 10}}},
+		{in: "{\"\xc0\xaf\": 1}", policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{"invalid UTF-8 in string literal", 3}}},
+		{in: `"hello\ud800world"`, policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{`invalid lone surrogate \ud800 in string literal`, 7}}},
+		{in: `"\uDd1e\uD834"`, policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{`invalid lone surrogate \uDd1e in string literal`, 2}}},
+		{in: `"\uD834\u1234"`, policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{`invalid lone surrogate \uD834 in string literal`, 2}}},
+		{in: `"\uD834"`, policy: UTF8Reject, decodeTest: decodeTest{err: &SyntaxError{`invalid lone surrogate \uD834 in string literal`, 2}}},
+
+		// pass
+		{in: "\"hello\xffworld\"", policy: UTF8Pass, decodeTest: decodeTest{expected: "hello\xffworld"}},
+		{in: "\"hello\xc2\xc2\\nworld\"", policy: UTF8Pass, decodeTest: decodeTest{expected: "hello\xc2\xc2\nworld"}},
+		{in: "\"hello\\ud800world\"", policy: UTF8Pass, decodeTest: decodeTest{expected: "hello\ufffdworld"}},
+	} {
+		for _, json5 := range []bool{false, true} {
+			d := NewDecoder([]byte(tt.in))
+			if json5 {
+				d.JSON5()
+			}
+			d.InvalidUTF8(tt.policy)
+			out, err := d.Decode()
+			if !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("#%d (json5: %v): %v, want %v", i, json5, err, tt.err)
+			}
+			if !reflect.DeepEqual(out, tt.expected) {
+				t.Errorf("#%d (json5: %v): %q, want %q", i, json5, out, tt.expected)
+			}
+		}
+	}
+}
+
+func TestInternKeys(t *testing.T) {
+	data := []byte(`[{"a":1,"b":{"a":2}},{"a":3,"b":{"a":4},"cd":5},{"cd":6,"e":7}]`)
+	d := NewDecoder(data)
+	d.InternKeys(3)
+	out, err := d.DecodeArray()
+	if err != nil {
+		t.Fatalf("expecting decode not to fail: %q", err)
+	}
+	expected, _ := DecodeArray(data)
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("compare to DecodeArray \n\tactual: %v\n\twant: %v", out, expected)
+	}
+	// the table is bounded.
+	if len(d.keys) != 3 {
+		t.Errorf("intern table size = %d; want 3", len(d.keys))
+	}
+	for _, k := range []string{"a", "b", "cd"} {
+		if _, ok := d.keys[k]; !ok {
+			t.Errorf("expecting %q to be interned", k)
+		}
+	}
+	if _, ok := d.keys["e"]; ok {
+		t.Error("expecting the table to be full before \"e\"")
+	}
+
+	// repeated keys do not allocate.
+	record := `{"event_type":"click","user_id":1,"country":"IL","ok":true}`
+	data = []byte("[" + strings.Repeat(record+",", 99) + record + "]")
+	allocs := func(intern bool) float64 {
+		return testing.AllocsPerRun(10, func() {
+			d := NewDecoder(data)
+			if intern {
+				d.InternKeys(100)
+			}
+			d.DecodeArray()
+		})
+	}
+	if with, without := allocs(true), allocs(false); without-with < 4*90 {
+		t.Errorf("allocations with intern table = %v; want at least %d less than %v", with, 4*90, without)
+	}
+}
+
+func TestReset(t *testing.T) {
+	d := NewDecoder(nil)
+	d.AllowComments()
+	d.InternKeys(10)
+	for i, tt := range []decodeTest{
+		{in: `{"a": 1} // comment`, expected: map[string]interface{}{"a": 1.0}},
+		{in: `{"a": "` + strings.Repeat(`\n`, 100) + `"}`, expected: map[string]interface{}{"a": strings.Repeat("\n", 100)}},
+		{in: `{"a": /* unterminated`, err: &SyntaxError{"unterminated block comment", 7}},
+		{in: `[{"a": "é"}, 2]`, expected: []interface{}{map[string]interface{}{"a": "é"}, 2.0}},
+		{in: `{"b": 1,}`, err: &SyntaxError{"invalid character '}' looking for beginning of object key string", 9}},
+		{in: `{"b": "` + strings.Repeat(`\t`, 50) + `"}`, expected: map[string]interface{}{"b": strings.Repeat("\t", 50)}},
+	} {
+		d.Reset([]byte(tt.in))
+		out, err := d.Decode()
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: %v, want %v", i, err, tt.err)
+		}
+		if !reflect.DeepEqual(out, tt.expected) {
+			t.Errorf("#%d: %v, want %v", i, out, tt.expected)
+		}
+	}
+	if len(d.keys) != 2 {
+		t.Errorf("intern table size = %d; want 2", len(d.keys))
+	}
+
+	// AllocString is applied to the new data.
+	d = NewDecoder([]byte(`"a"`))
+	d.AllocString()
+	d.Reset([]byte(`"b"`))
+	if out, err := d.Decode(); err != nil || out != "b" {
+		t.Errorf("%v, %v; want b", out, err)
+	}
+
+	// the lenient mode is not kept.
+	d = NewDecoder([]byte(`[x]`))
+	if _, errs := d.DecodeLenient(); len(errs) != 1 {
+		t.Errorf("expecting 1 error, got: %v", errs)
+	}
+	d.Reset([]byte(`[x]`))
+	if _, err := d.Decode(); err == nil {
+		t.Error("expecting decode to fail after Reset")
+	}
+}
@@ -0,0 +1,248 @@
+package djson
+
+import (
+	"errors"
+	"strconv"
+)
+
+// EachPath scans the JSON-encoded data once, and calls cb with the value of
+// each of the given paths as it is encountered, like jsonparser's EachKey.
+// A path is a list of object keys and array indexes in brackets(e.g.
+// []string{"users", "[0]", "name"}). cb gets the index of the path in paths,
+// its value as it is returned by Decode, and the type of the value.
+//
+//	paths := [][]string{{"APP_ID"}, {"Image", "Src"}, {"Tags", "[0]"}}
+//	err := djson.EachPath(data, paths, func(idx int, v interface{}, t djson.ValueType) error {
+//		fmt.Println(paths[idx], v, t)
+//		return nil
+//	})
+//
+// Only the values of the paths are decoded, and the rest of the document is
+// skipped without allocating. Each path is reported at most once; If it
+// occurs more than once(i.e. duplicate keys), only its first occurrence is
+// reported, and paths that are not in the document are not reported at all.
+// Note that this deliberately differs from Decode, Lazy and SetBytes, where
+// the last occurrence of a duplicate key wins; The values are reported as
+// they are encountered, without buffering them until the end of the
+// document, and a later duplicate can not take back a value that was
+// already reported. Use Decode if the last occurrence must win.
+// Paths that are nested in the value of another path are reported right
+// after it, in an unspecified order.
+//
+// The scanning stops as soon as all the paths were found, or when cb returns
+// an error, which is then returned by EachPath. Syntax errors are reported
+// like in Decode, but note that the part of the document that follows the
+// last path is not validated.
+func EachPath(data []byte, paths [][]string, cb func(idx int, v interface{}, t ValueType) error) error {
+	root := new(pathNode)
+	for i, path := range paths {
+		root.add(path, i)
+	}
+	d := getDecoder(data)
+	defer putDecoder(d)
+	s := &pathScanner{d: d, cb: cb, found: make([]bool, len(paths)), left: len(paths)}
+	switch err := s.value(root); err {
+	case errAllFound:
+		return nil
+	case nil:
+	default:
+		return err
+	}
+	if c := d.skipSpaces(); d.pos < d.end {
+		return d.error(c, "after top-level value")
+	}
+	return nil
+}
+
+// pathNode is a node in the trie of the paths of EachPath.
+type pathNode struct {
+	ends  []int                // indexes of the paths that end at this node
+	keys  map[string]*pathNode // paths that continue with an object key
+	elems map[int]*pathNode    // paths that continue with an array index
+}
+
+// add adds the path with the given index to the trie.
+func (n *pathNode) add(path []string, idx int) {
+	for _, k := range path {
+		var next *pathNode
+		if i, ok := arrayIndex(k); ok {
+			if n.elems == nil {
+				n.elems = make(map[int]*pathNode)
+			}
+			if next = n.elems[i]; next == nil {
+				next = new(pathNode)
+				n.elems[i] = next
+			}
+		} else {
+			if n.keys == nil {
+				n.keys = make(map[string]*pathNode)
+			}
+			if next = n.keys[k]; next == nil {
+				next = new(pathNode)
+				n.keys[k] = next
+			}
+		}
+		n = next
+	}
+	n.ends = append(n.ends, idx)
+}
+
+// arrayIndex parses an array index in brackets(e.g. "[2]"), and reports
+// whether k is one.
+func arrayIndex(k string) (int, bool) {
+	if len(k) < 3 || k[0] != '[' || k[len(k)-1] != ']' {
+		return 0, false
+	}
+	i, err := strconv.Atoi(k[1 : len(k)-1])
+	return i, err == nil && i >= 0
+}
+
+// errAllFound stops the scanning of EachPath once all the paths were found.
+var errAllFound = errors.New("djson: all paths were found")
+
+// pathScanner holds the state of EachPath.
+type pathScanner struct {
+	d     *Decoder
+	cb    func(int, interface{}, ValueType) error
+	found []bool
+	left  int
+}
+
+// value scans the value at the current position, that is at the given node
+// of the trie. Values that are not on any of the paths are skipped.
+func (s *pathScanner) value(n *pathNode) error {
+	d := s.d
+	if len(n.ends) > 0 {
+		v, err := d.any()
+		if err != nil {
+			return err
+		}
+		return s.emit(n, v)
+	}
+	switch c := d.skipSpaces(); {
+	case c == '{' && n.keys != nil:
+		return s.object(n)
+	case c == '[' && n.elems != nil:
+		return s.array(n)
+	}
+	return d.skip()
+}
+
+// object is the `value` version of `object`.
+func (s *pathScanner) object(n *pathNode) error {
+	d := s.d
+	d.pos++
+	if d.skipSpaces() == '}' {
+		d.pos++
+		return nil
+	}
+	for {
+		c := d.skipSpaces()
+		if c != '"' {
+			return d.error(c, "looking for beginning of object key string")
+		}
+		start := d.pos + 1
+		unquote, err := d.scanString()
+		if err != nil {
+			return err
+		}
+		data := d.data[start : d.pos-1]
+		if unquote {
+			var (
+				ok       bool
+				stackbuf [64]byte
+			)
+			if data, ok = d.unquote(data, stackbuf[:]); !ok {
+				return ErrStringEscape
+			}
+		}
+		if c := d.skipSpaces(); c != ':' {
+			return d.error(c, "after object key")
+		}
+		d.pos++
+		if next := n.keys[string(data)]; next != nil {
+			err = s.value(next)
+		} else {
+			err = d.skip()
+		}
+		if err != nil {
+			return err
+		}
+		switch c := d.skipSpaces(); c {
+		case ',':
+			d.pos++
+		case '}':
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "after object key:value pair")
+		}
+	}
+}
+
+// array is the `value` version of `array`.
+func (s *pathScanner) array(n *pathNode) error {
+	d := s.d
+	d.pos++
+	if d.skipSpaces() == ']' {
+		d.pos++
+		return nil
+	}
+	for i := 0; ; i++ {
+		var err error
+		if next := n.elems[i]; next != nil {
+			err = s.value(next)
+		} else {
+			err = d.skip()
+		}
+		if err != nil {
+			return err
+		}
+		switch c := d.skipSpaces(); c {
+		case ',':
+			d.pos++
+		case ']':
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "after array element")
+		}
+	}
+}
+
+// emit calls the callback with the paths that end at the given node, and
+// with the paths below it, which are looked up in the decoded value.
+func (s *pathScanner) emit(n *pathNode, v interface{}) error {
+	for _, idx := range n.ends {
+		if s.found[idx] {
+			continue
+		}
+		s.found[idx] = true
+		s.left--
+		if err := s.cb(idx, v, Type(v)); err != nil {
+			return err
+		}
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, next := range n.keys {
+			if v, ok := v[k]; ok {
+				if err := s.emit(next, v); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		for i, next := range n.elems {
+			if i < len(v) {
+				if err := s.emit(next, v[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if s.left == 0 {
+		return errAllFound
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package djson
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEachPath(t *testing.T) {
+	full, _ := DecodeObject(allValueIndent)
+	object6 := full["object_6"].(map[string]interface{})
+	paths := [][]string{
+		{"string_2"},
+		{"int_2"},
+		{"array_3", "[1]"},
+		{"array_5", "[7]", "array_5_1", "[0]", "array_5_1_2"},
+		{"object_6", "c", "cc"},
+		{"object_6", "c", "cc", "ccd", "ccda", "[5]"},
+		{"object_6", "c", "cc", "cca", "[9]"},
+		{"object_6"},
+		{"missing"},
+		{"array_2", "[9]"},
+		{"string_1", "[0]"},
+		{"int_2"},
+	}
+	expected := map[int]interface{}{
+		0:  "Déjà vu",
+		1:  -1.0,
+		2:  "a",
+		3:  []interface{}{1.0, 2.0, 2.0, 3.0, 4.0, 5.0, 5.0, 6.0, 0.0, 7.0, 7.0},
+		4:  object6["c"].(map[string]interface{})["cc"],
+		5:  6.0,
+		7:  object6,
+		11: -1.0,
+	}
+	found := make(map[int]interface{})
+	err := EachPath(allValueIndent, paths, func(idx int, v interface{}, typ ValueType) error {
+		if _, ok := found[idx]; ok {
+			t.Errorf("path %v is reported more than once", paths[idx])
+		}
+		if typ != Type(v) {
+			t.Errorf("path %v: type = %v; want %v", paths[idx], typ, Type(v))
+		}
+		found[idx] = v
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPath: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("EachPath = %v; want %v", found, expected)
+	}
+
+	// the first occurrence of a duplicate key is reported, unlike in Decode.
+	EachPath([]byte(`{"ab": 1, "ab": 2, "c": [{"d": 3}]}`), [][]string{{"ab"}, {"c", "[0]", "d"}}, func(idx int, v interface{}, _ ValueType) error {
+		if want := []float64{1, 3}[idx]; v != want {
+			t.Errorf("path %d = %v; want %v", idx, v, want)
+		}
+		return nil
+	})
+
+	// the errors of the callback stop the scanning.
+	errStop := errors.New("stop")
+	var calls int
+	err = EachPath(allValueIndent, paths, func(int, interface{}, ValueType) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("EachPath = %v after %d calls; want %v after 1", err, calls, errStop)
+	}
+
+	// the rest of the document is not scanned once all the paths were found.
+	if err := EachPath([]byte(`{"a": 1, "b": }`), [][]string{{"a"}}, func(int, interface{}, ValueType) error { return nil }); err != nil {
+		t.Errorf("EachPath: unexpected error: %v", err)
+	}
+}
+
+func TestEachPathErrors(t *testing.T) {
+	var inputs []string
+	for _, tt := range decodeTests {
+		inputs = append(inputs, tt.in, `{"a": [1, {"b": `+tt.in+`}]}`)
+	}
+	files, _ := filepath.Glob("testdata/JSONTestSuite/test_parsing/*.json")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, string(data), `{"a": [1, {"b": `+string(data)+`}]}`)
+	}
+	// the paths are not in the inputs, so the whole document is validated.
+	paths := [][]string{{"a", "[0]", "missing"}, {"a", "[1]", "c"}, {"[0]", "x"}}
+	for _, in := range inputs {
+		_, expected := Decode([]byte(in))
+		err := EachPath([]byte(in), paths, func(idx int, v interface{}, _ ValueType) error {
+			t.Errorf("EachPath(%.40q): unexpected path %v = %v", in, paths[idx], v)
+			return nil
+		})
+		if !reflect.DeepEqual(err, expected) {
+			t.Errorf("EachPath(%.40q): expecting the same error as Decode\n\tactual: %v\n\twant: %v", in, err, expected)
+		}
+	}
+}
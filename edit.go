@@ -0,0 +1,337 @@
+package djson
+
+// SetBytes sets the value in the given path of the JSON-encoded data, without
+// decoding it, and returns the new data. The value is the JSON encoding of the
+// new value, and the path is a list of object keys and array indexes in
+// brackets(e.g. "users", "[0]", "name"), like in Set.
+//
+// The data is scanned up to the target of the path, and the output is spliced
+// from the data and the value, so all the other bytes, including white spaces
+// and the order of the keys, are preserved. If the path exists, its value is
+// replaced(or the value of the last occurrence of a duplicate key, as it is
+// the one that Decode returns). Otherwise, the missing members, elements and
+// their containers are added like in Set, and they are written without white
+// spaces. Nulls in the path are replaced as well. Like in Set, arrays can be
+// extended only by appending.
+//
+//	data, err = djson.SetBytes(data, []byte(`"IL"`), "geo", "country")
+//
+// The value is validated, and a *SyntaxError is returned if it is invalid, or
+// if the data is invalid up to the target. A *PathError is returned if the
+// path goes through a value that is not an object or an array, or through an
+// index that is beyond the end of an array. Note that the
+// part of the data after the target is not validated. The data is not
+// modified, and the result is a new slice.
+func SetBytes(data, value []byte, path ...string) ([]byte, error) {
+	d := getDecoder(value)
+	defer putDecoder(d)
+	d.skipSpaces()
+	if err := d.skip(); err != nil {
+		return nil, err
+	}
+	if c := d.skipSpaces(); d.pos < d.end {
+		return nil, d.error(c, "after top-level value")
+	}
+
+	d.Reset(data)
+	loc, err := d.locate(path)
+	if err != nil {
+		return nil, err
+	}
+	if loc.found || loc.null {
+		// the rest of the path, if any, goes through new containers.
+		if err := newIndexes(path, loc.depth); err != nil {
+			return nil, err
+		}
+		var e Encoder
+		e.nest(path[loc.depth:], value)
+		return splice(data, loc.start, loc.end, e.buf), nil
+	}
+
+	// add the missing element to the container it was not found in.
+	var (
+		e    Encoder
+		rest = path[loc.depth:]
+		pos  = loc.open + 1
+	)
+	if n := len(loc.elems); n > 0 {
+		pos = loc.elems[n-1].end
+		e.buf = append(e.buf, ',')
+	}
+	if loc.array {
+		if n, _ := arrayIndex(rest[0]); n > len(loc.elems) {
+			return nil, indexRangeError(path[:loc.depth+1], len(loc.elems))
+		}
+	} else {
+		e.string(rest[0])
+		e.buf = append(e.buf, ':')
+	}
+	if err := newIndexes(path, loc.depth+1); err != nil {
+		return nil, err
+	}
+	e.nest(rest[1:], value)
+	return splice(data, pos, pos, e.buf), nil
+}
+
+// DeleteBytes deletes the value in the given path of the JSON-encoded data,
+// without decoding it, and returns the new data. Like SetBytes, the rest of
+// the data is preserved, and the comma that separated the deleted member or
+// element is removed with it. All the occurrences of a duplicate key are
+// deleted.
+//
+// If the path does not exist, the data is returned as is. A *SyntaxError is
+// returned if the data is invalid up to the target of the path. The data is
+// not modified. An empty path deletes the whole data, and returns nil.
+func DeleteBytes(data []byte, path ...string) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	d := getDecoder(data)
+	defer putDecoder(d)
+	loc, err := d.locate(path)
+	if _, ok := err.(*PathError); ok {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !loc.found {
+		return data, nil
+	}
+	out := make([]byte, 0, len(data))
+	prev := 0
+	// each run of consecutive elements is removed with the comma that
+	// follows it, or with the comma that precedes it if it is the last one.
+	for i := 0; i < len(loc.matches); {
+		j := i
+		for j+1 < len(loc.matches) && loc.matches[j+1] == loc.matches[j]+1 {
+			j++
+		}
+		first, last := loc.matches[i], loc.matches[j]
+		var from, to int
+		switch {
+		case last+1 < len(loc.elems):
+			from, to = loc.elems[first].start, loc.elems[last+1].start
+		case first > 0:
+			from, to = loc.elems[first-1].end, loc.elems[last].end
+		default:
+			from, to = loc.elems[first].start, loc.elems[last].end
+		}
+		out = append(out, data[prev:from]...)
+		prev = to
+		i = j + 1
+	}
+	return append(out, data[prev:]...), nil
+}
+
+// splice returns a new slice, where the range [start, end) of data is
+// replaced with s.
+func splice(data []byte, start, end int, s []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(s))
+	out = append(out, data[:start]...)
+	out = append(out, s...)
+	return append(out, data[end:]...)
+}
+
+// newIndexes returns a *PathError if one of the elements of the path from the
+// given position is an array index other than 0, as they go through new
+// arrays, which can only be appended to.
+func newIndexes(path []string, from int) error {
+	for i := from; i < len(path); i++ {
+		if n, ok := arrayIndex(path[i]); ok && n > 0 {
+			return indexRangeError(path[:i+1], 0)
+		}
+	}
+	return nil
+}
+
+// nest writes the value nested in the given path, where each object key and
+// array index(that is 0) in the path is a new object or array.
+func (e *Encoder) nest(path []string, value []byte) {
+	if len(path) == 0 {
+		e.buf = append(e.buf, value...)
+		return
+	}
+	if _, ok := arrayIndex(path[0]); ok {
+		e.buf = append(e.buf, '[')
+		e.nest(path[1:], value)
+		e.buf = append(e.buf, ']')
+		return
+	}
+	e.buf = append(e.buf, '{')
+	e.string(path[0])
+	e.buf = append(e.buf, ':')
+	e.nest(path[1:], value)
+	e.buf = append(e.buf, '}')
+}
+
+// element is the location of an object member or an array element.
+type element struct {
+	start int // the start of the key of the member, or of the element
+	value int // the start of the value
+	end   int // the end of the value
+}
+
+// location is the result of locate.
+type location struct {
+	// found reports whether the path was found, and start and end hold the
+	// span of its value.
+	found      bool
+	start, end int
+	// null reports whether the search stopped at a null value, and start
+	// and end hold its span.
+	null bool
+	// depth is the number of elements of the path that were found.
+	depth int
+	// open is the position of the container that holds path[depth], or the
+	// last element of the path if it was found, and array reports whether
+	// it is an array. elems holds its scanned elements, and matches holds
+	// the positions of the elements of the path in elems.
+	open    int
+	array   bool
+	elems   []element
+	matches []int
+}
+
+// locate scans the data up to the given path, and returns its location.
+// It returns a *PathError if the path goes through a value that is not an
+// object, an array or null.
+func (d *Decoder) locate(path []string) (*location, error) {
+	loc := new(location)
+	c := d.skipSpaces()
+	for depth, k := range path {
+		loc.depth = depth
+		loc.open = d.pos
+		loc.elems, loc.matches = loc.elems[:0], loc.matches[:0]
+		var err error
+		switch c {
+		case '{':
+			loc.array = false
+			err = d.locateMember(loc, k)
+		case '[':
+			n, ok := arrayIndex(k)
+			if !ok {
+				return nil, notIndexError(path[:depth+1])
+			}
+			loc.array = true
+			err = d.locateElement(loc, n)
+		default:
+			start := d.pos
+			if err := d.skip(); err != nil {
+				return nil, err
+			}
+			if c == 'n' {
+				loc.null, loc.start, loc.end = true, start, d.pos
+				return loc, nil
+			}
+			return nil, notContainerError(path[:depth+1], rawType(c))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(loc.matches) == 0 {
+			return loc, nil
+		}
+		d.pos = loc.elems[loc.matches[len(loc.matches)-1]].value
+		c = d.data[d.pos]
+	}
+	loc.found = true
+	loc.depth = len(path)
+	loc.start = d.pos
+	if len(path) == 0 {
+		if err := d.skip(); err != nil {
+			return nil, err
+		}
+		loc.end = d.pos
+	} else {
+		loc.end = loc.elems[loc.matches[len(loc.matches)-1]].end
+	}
+	return loc, nil
+}
+
+// locateMember scans the object at the current position, and collects its
+// members, and the positions of the members with the given key.
+func (d *Decoder) locateMember(loc *location, k string) error {
+	d.pos++
+	if d.skipSpaces() == '}' {
+		d.pos++
+		return nil
+	}
+	for {
+		c := d.skipSpaces()
+		if c != '"' {
+			return d.error(c, "looking for beginning of object key string")
+		}
+		start := d.pos
+		unquote, err := d.scanString()
+		if err != nil {
+			return err
+		}
+		key := d.data[start+1 : d.pos-1]
+		if unquote {
+			var (
+				ok       bool
+				stackbuf [64]byte
+			)
+			if key, ok = d.unquote(key, stackbuf[:]); !ok {
+				return ErrStringEscape
+			}
+		}
+		if string(key) == k {
+			loc.matches = append(loc.matches, len(loc.elems))
+		}
+		if c := d.skipSpaces(); c != ':' {
+			return d.error(c, "after object key")
+		}
+		d.pos++
+		d.skipSpaces()
+		value := d.pos
+		if err := d.skip(); err != nil {
+			return err
+		}
+		loc.elems = append(loc.elems, element{start, value, d.pos})
+		switch c := d.skipSpaces(); c {
+		case ',':
+			d.pos++
+		case '}':
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "after object key:value pair")
+		}
+	}
+}
+
+// locateElement scans the array at the current position up to the element
+// that follows the n'th element, and collects its elements.
+func (d *Decoder) locateElement(loc *location, n int) error {
+	d.pos++
+	if d.skipSpaces() == ']' {
+		d.pos++
+		return nil
+	}
+	for i := 0; ; i++ {
+		d.skipSpaces()
+		start := d.pos
+		if err := d.skip(); err != nil {
+			return err
+		}
+		if i == n {
+			loc.matches = append(loc.matches, i)
+		}
+		loc.elems = append(loc.elems, element{start, start, d.pos})
+		if i > n {
+			// the element that follows the target is needed by DeleteBytes.
+			return nil
+		}
+		switch c := d.skipSpaces(); c {
+		case ',':
+			d.pos++
+		case ']':
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "after array element")
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package djson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetBytes(t *testing.T) {
+	for _, tt := range []struct {
+		in    string
+		value string
+		path  []string
+		out   string
+		err   string
+	}{
+		{`{"a": 1, "b": 2}`, `3`, []string{"b"}, `{"a": 1, "b": 3}`, ""},
+		{"{\n  \"a\": 1\n}", `true`, []string{"c", "d"}, "{\n  \"a\": 1,\"c\":{\"d\":true}\n}", ""},
+		{`{}`, ` 1 `, []string{"a"}, `{"a": 1 }`, ""},
+		{`{ }`, `"<x>"`, []string{"a<"}, `{"a\u003c":"<x>" }`, ""},
+		{`[1, 2]`, `4`, []string{"[2]"}, `[1, 2,4]`, ""},
+		{`[]`, `4`, []string{"[0]", "[0]", "a"}, `[[{"a":4}]]`, ""},
+		{`{"a": null, "b": [null]}`, `"x"`, []string{"a", "[0]"}, `{"a": ["x"], "b": [null]}`, ""},
+		{`{"a": 1, "b": [null]}`, `"x"`, []string{"b", "[0]", "c"}, `{"a": 1, "b": [{"c":"x"}]}`, ""},
+		{`{"a": 1, "a": {"b": 2}}`, `3`, []string{"a", "b"}, `{"a": 1, "a": {"b": 3}}`, ""},
+		{`{"ab": 1}`, `2`, []string{"ab"}, `{"ab": 2}`, ""},
+		{` [1, 2] `, `{"a": [1]}`, nil, ` {"a": [1]} `, ""},
+		{`{"a": [1, {"b": 2}, 3]}`, `4`, []string{"a", "[1]", "b"}, `{"a": [1, {"b": 4}, 3]}`, ""},
+		{`{"a": "b"}`, `1`, []string{"a", "c"}, "", `can not set ["a" "c"] in a string value`},
+		{`{"a": [1]}`, `1`, []string{"a", "c"}, "", `can not set ["a" "c"] in an array: "c" is not an array index`},
+		{`{"a": [1]}`, `1`, []string{"a", "[0]", "b"}, "", `can not set ["a" "[0]" "b"] in a number value`},
+		{`[1, 2]`, `4`, []string{"[3]"}, "", `can not set ["[3]"]: index out of range for an array of length 2`},
+		{`[]`, `4`, []string{"[1]", "[0]", "a"}, "", `can not set ["[1]"]: index out of range for an array of length 0`},
+		{`{"a": null}`, `"x"`, []string{"a", "[1]"}, "", `can not set ["a" "[1]"]: index out of range for an array of length 0`},
+		{`{}`, `"x"`, []string{"a", "[0]", "[1]"}, "", `can not set ["a" "[0]" "[1]"]: index out of range for an array of length 0`},
+		{`{"a": 1}`, `x`, []string{"a"}, "", `invalid character 'x' looking for beginning of value`},
+		{`{"a": 1}`, `1 2`, []string{"a"}, "", `invalid character '2' after top-level value`},
+		{`{"a": 1}`, ``, []string{"a"}, "", `unexpected end of JSON input`},
+		{`{"a" 1}`, `1`, []string{"a"}, "", `invalid character '1' after object key`},
+		{`{"a": [1 2]}`, `1`, []string{"a", "[1]"}, "", `invalid character '2' after array element`},
+		{`{"a": tru}`, `1`, []string{"a"}, "", `invalid character 'r' in literal true`},
+	} {
+		out, err := SetBytes([]byte(tt.in), []byte(tt.value), tt.path...)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("SetBytes(%s, %s, %q): error = %v; want %q", tt.in, tt.value, tt.path, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || string(out) != tt.out {
+			t.Errorf("SetBytes(%s, %s, %q) = %s, %v; want %s", tt.in, tt.value, tt.path, out, err, tt.out)
+			continue
+		}
+		// the result is the same as setting the value in the decoded tree.
+		root, _ := Decode([]byte(tt.in))
+		value, _ := Decode([]byte(tt.value))
+		expected, _ := Set(root, tt.path, value)
+		if val, err := Decode(out); err != nil || !reflect.DeepEqual(val, expected) {
+			t.Errorf("SetBytes(%s, %s, %q): decoded = %v, %v; want %v", tt.in, tt.value, tt.path, val, err, expected)
+		}
+	}
+
+	// the path errors are the same as in Set.
+	root, _ := Decode(allValueIndent)
+	for _, path := range [][]string{
+		{"string_1", "a"},
+		{"array_2", "x"},
+		{"object_6", "c", "cc", "cca", "[7]", "a"},
+		{"array_2", "[1000000000]"},
+		{"array_2", "[4]", "[1]"},
+		{"new", "[1]"},
+		{"new", "[0]", "[0]", "a", "[2]"},
+		{"null_1", "[1]"},
+	} {
+		_, expected := Set(root, path, 1.0)
+		if _, err := SetBytes(allValueIndent, []byte("1"), path...); !reflect.DeepEqual(err, expected) {
+			t.Errorf("SetBytes(%q): error = %v; want %v", path, err, expected)
+		}
+	}
+}
+
+func TestDeleteBytes(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		path []string
+		out  string
+		err  string
+	}{
+		{`{"a": 1, "b": 2}`, []string{"a"}, `{"b": 2}`, ""},
+		{`{"a": 1, "b": 2}`, []string{"b"}, `{"a": 1}`, ""},
+		{"{\n\t\"a\": 1,\n\t\"b\": 2,\n\t\"c\": 3\n}", []string{"b"}, "{\n\t\"a\": 1,\n\t\"c\": 3\n}", ""},
+		{`{ "a": 1 }`, []string{"a"}, `{  }`, ""},
+		{`[1, 2, 3]`, []string{"[1]"}, `[1, 3]`, ""},
+		{`[1, 2, 3]`, []string{"[2]"}, `[1, 2]`, ""},
+		{`[[1, 2], 3]`, []string{"[0]", "[0]"}, `[[2], 3]`, ""},
+		{`{"a":1,"a":2,"b":3,"a":4}`, []string{"a"}, `{"b":3}`, ""},
+		{`{"a":1,"b":3,"a":4,"a":5}`, []string{"a"}, `{"b":3}`, ""},
+		{`{"a":1, "a":2}`, []string{"a"}, `{}`, ""},
+		{`{"a": {"b": 1, "c": [1]}}`, []string{"a", "c", "[0]"}, `{"a": {"b": 1, "c": []}}`, ""},
+		{`{"a": 1}`, []string{"b"}, `{"a": 1}`, ""},
+		{`{"a": 1}`, []string{"a", "b"}, `{"a": 1}`, ""},
+		{`{"a": null}`, []string{"a", "b"}, `{"a": null}`, ""},
+		{`{"a": [1]}`, []string{"a", "b"}, `{"a": [1]}`, ""},
+		{`{"a": [1]}`, []string{"a", "[1]"}, `{"a": [1]}`, ""},
+		{`{"a": 1}`, nil, ``, ""},
+		{`{"a": [1, x]}`, []string{"a", "[1]"}, "", `invalid character 'x' looking for beginning of value`},
+		{`{"a": [1, 2}`, []string{"a", "[2]"}, "", `invalid character '}' after array element`},
+	} {
+		out, err := DeleteBytes([]byte(tt.in), tt.path...)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("DeleteBytes(%s, %q): error = %v; want %q", tt.in, tt.path, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || string(out) != tt.out {
+			t.Errorf("DeleteBytes(%s, %q) = %s, %v; want %s", tt.in, tt.path, out, err, tt.out)
+			continue
+		}
+		if len(tt.path) == 0 {
+			continue
+		}
+		// the result is the same as deleting the value from the decoded tree.
+		root, _ := Decode([]byte(tt.in))
+		expected, _ := Delete(root, tt.path)
+		if val, err := Decode(out); err != nil || !reflect.DeepEqual(val, expected) {
+			t.Errorf("DeleteBytes(%s, %q): decoded = %v, %v; want %v", tt.in, tt.path, val, err, expected)
+		}
+	}
+
+	// the data is not modified.
+	data := []byte(`{"a": 1, "b": 2}`)
+	DeleteBytes(data, "a")
+	SetBytes(data, []byte("3"), "b")
+	if string(data) != `{"a": 1, "b": 2}` {
+		t.Errorf("expecting the data not to be modified, got: %s", data)
+	}
+}
@@ -0,0 +1,202 @@
+package djson
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Encoder is the object that holds the state of the encoding
+type Encoder struct {
+	buf []byte
+	nan bool
+}
+
+// NewEncoder creates new Encoder
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// AllowNaN makes the Encoder write NaN, +Inf and -Inf numbers as the NaN,
+// Infinity and -Infinity literals, which can be read back by a Decoder with
+// the AllowNaN option. By default, these numbers are rejected with a
+// json.UnsupportedValueError like in json.Marshal.
+func (e *Encoder) AllowNaN() {
+	e.nan = true
+}
+
+// Encode returns the JSON encoding of v.
+// The value v is expected to be one of the types that are returned
+// by Decode:
+//
+//	bool, for JSON booleans
+//	float64, for JSON numbers
+//	string, for JSON strings
+//	[]interface{}, for JSON arrays
+//	map[string]interface{}, for JSON objects
+//	nil for JSON null
+//
+// Other types are encoded using json.Marshal. Note that the output is
+// compatible with json.Marshal; object keys are sorted, and strings are
+// escaped the same way.
+func (e *Encoder) Encode(v interface{}) ([]byte, error) {
+	e.buf = e.buf[:0]
+	if err := e.value(v); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), e.buf...), nil
+}
+
+// Encode returns the JSON encoding of v. See Encoder.Encode for more details.
+func Encode(v interface{}) ([]byte, error) {
+	e := NewEncoder()
+	if err := e.value(v); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+func (e *Encoder) value(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		e.buf = append(e.buf, "null"...)
+	case bool:
+		e.buf = strconv.AppendBool(e.buf, v)
+	case float64:
+		return e.float(v)
+	case string:
+		e.string(v)
+	case []interface{}:
+		e.buf = append(e.buf, '[')
+		for i, v := range v {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := e.value(v); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		e.buf = append(e.buf, '{')
+		for i, k := range keys {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.string(k)
+			e.buf = append(e.buf, ':')
+			if err := e.value(v[k]); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, '}')
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		e.buf = append(e.buf, b...)
+	}
+	return nil
+}
+
+// float encodes f like json.Marshal does, except for the NaN and Infinity
+// values that are allowed with AllowNaN.
+func (e *Encoder) float(f float64) error {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		if !e.nan {
+			return &json.UnsupportedValueError{Value: reflect.ValueOf(f), Str: strconv.FormatFloat(f, 'g', -1, 64)}
+		}
+		switch {
+		case math.IsNaN(f):
+			e.buf = append(e.buf, "NaN"...)
+		case f > 0:
+			e.buf = append(e.buf, "Infinity"...)
+		default:
+			e.buf = append(e.buf, "-Infinity"...)
+		}
+		return nil
+	}
+	// convert as if by ES6 number to string conversion.
+	abs, fmt := math.Abs(f), byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		fmt = 'e'
+	}
+	e.buf = strconv.AppendFloat(e.buf, f, fmt, -1, 64)
+	if fmt == 'e' {
+		// clean up e-09 to e-9
+		if n := len(e.buf); n >= 4 && e.buf[n-4] == 'e' && e.buf[n-3] == '-' && e.buf[n-2] == '0' {
+			e.buf[n-2] = e.buf[n-1]
+			e.buf = e.buf[:n-1]
+		}
+	}
+	return nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// string encodes s like json.Marshal does, including the HTML escaping.
+// Most of the code in this method copied from the Go standard library,
+// encoding/json/encode.go
+func (e *Encoder) string(s string) {
+	e.buf = append(e.buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= ' ' && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			e.buf = append(e.buf, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				e.buf = append(e.buf, '\\', b)
+			case '\n':
+				e.buf = append(e.buf, '\\', 'n')
+			case '\r':
+				e.buf = append(e.buf, '\\', 'r')
+			case '\t':
+				e.buf = append(e.buf, '\\', 't')
+			case '\b':
+				e.buf = append(e.buf, '\\', 'b')
+			case '\f':
+				e.buf = append(e.buf, '\\', 'f')
+			default:
+				// control characters and <, >, & are written as \u00XX.
+				e.buf = append(e.buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			e.buf = append(e.buf, s[start:i]...)
+			e.buf = append(e.buf, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 is LINE SEPARATOR and U+2029 is PARAGRAPH SEPARATOR. They are
+		// valid JSON, but not valid JavaScript, so they are escaped as well.
+		if c == '\u2028' || c == '\u2029' {
+			e.buf = append(e.buf, s[start:i]...)
+			e.buf = append(e.buf, '\\', 'u', '2', '0', '2', hexDigits[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	e.buf = append(e.buf, s[start:]...)
+	e.buf = append(e.buf, '"')
+}
@@ -0,0 +1,123 @@
+package djson
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for i, v := range []interface{}{
+		nil,
+		true,
+		false,
+		0.0,
+		-5.0,
+		3.1415926,
+		1e21,
+		1e-7,
+		-2.99792458e-8,
+		"",
+		"Déjà vu",
+		"\"foobar\"<html> [   ] & \\ \b\f\n\r\t\x00\x1f",
+		"invalid \xff utf-8",
+		[]interface{}{},
+		[]interface{}{1.0, "a", nil, []interface{}{true}},
+		map[string]interface{}{},
+		map[string]interface{}{"b": 1.0, "a": map[string]interface{}{"c": []interface{}{}}},
+		// types that are not returned by Decode
+		1,
+		[]string{"a"},
+	} {
+		expected, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("#%d: expecting std json not to fail: %q", i, err)
+		}
+		actual, err := Encode(v)
+		if err != nil {
+			t.Errorf("#%d: expecting encode not to fail: %q", i, err)
+		}
+		if string(actual) != string(expected) {
+			t.Errorf("#%d: %s, want %s", i, actual, expected)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	v, err := Decode(allValueIndent)
+	if err != nil {
+		t.Fatalf("expecting decode not to fail: %q", err)
+	}
+	expected, _ := json.Marshal(v)
+	actual, err := NewEncoder().Encode(v)
+	if err != nil {
+		t.Fatalf("expecting encode not to fail: %q", err)
+	}
+	if string(actual) != string(expected) {
+		t.Errorf("compare to std marshaler \n\tactual: %s\n\twant: %s", actual, expected)
+	}
+}
+
+func TestAllowNaN(t *testing.T) {
+	for i, tt := range []decodeTest{
+		{in: `Infinity`, expected: math.Inf(1)},
+		{in: `-Infinity`, expected: math.Inf(-1)},
+		{in: `[1, Infinity, -Infinity, -1]`, expected: []interface{}{1.0, math.Inf(1), math.Inf(-1), -1.0}},
+		{in: `{"a": -Infinity}`, expected: map[string]interface{}{"a": math.Inf(-1)}},
+		{in: `+Infinity`, err: &SyntaxError{"invalid character '+' looking for beginning of value", 1}},
+		{in: `-NaN`, err: &SyntaxError{"invalid character 'N' in negative numeric literal", 2}},
+		{in: `Infinty`, err: &SyntaxError{"invalid character 't' in literal Infinity", 6}},
+		{in: `-Inf`, err: ErrUnexpectedEOF},
+		{in: `Na`, err: ErrUnexpectedEOF},
+	} {
+		d := NewDecoder([]byte(tt.in))
+		d.AllowNaN()
+		out, err := d.Decode()
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: %v, want %v", i, err, tt.err)
+		}
+		if err == nil && !equalNaN(out, tt.expected) {
+			t.Errorf("#%d: %v, want %v", i, out, tt.expected)
+		}
+	}
+
+	d := NewDecoder([]byte(`[NaN,Infinity,-Infinity]`))
+	d.AllowNaN()
+	v, err := d.Decode()
+	if err != nil {
+		t.Fatalf("expecting decode not to fail: %q", err)
+	}
+	if f := v.([]interface{})[0].(float64); !math.IsNaN(f) {
+		t.Errorf("%v, want NaN", f)
+	}
+	if _, err := Encode(v); err == nil {
+		t.Error("expecting encode to fail without AllowNaN")
+	}
+	e := NewEncoder()
+	e.AllowNaN()
+	out, err := e.Encode(v)
+	if err != nil {
+		t.Fatalf("expecting encode not to fail: %q", err)
+	}
+	if string(out) != `[NaN,Infinity,-Infinity]` {
+		t.Errorf("%s, want %s", out, `[NaN,Infinity,-Infinity]`)
+	}
+
+	// the literals are not allowed by default
+	for _, in := range []string{`NaN`, `Infinity`, `-Infinity`} {
+		if _, err := Decode([]byte(in)); err == nil {
+			t.Errorf("expecting %s to fail without AllowNaN", in)
+		}
+	}
+}
+
+// equalNaN compares the encoding of the given values, as NaN is not equal
+// to itself.
+func equalNaN(a, b interface{}) bool {
+	e := NewEncoder()
+	e.AllowNaN()
+	out, _ := e.Encode(a)
+	exp, _ := e.Encode(b)
+	return string(out) == string(exp)
+}
@@ -97,3 +97,26 @@ func ExampleDecoder_AllocString() {
 	// - Output:
 	// map[count:93 userid:4234A event_type:click]
 }
+
+func ExampleDecoder_Only() {
+	var data = []byte(`{
+		"ID": 76523,
+		"IP": "69.89.31.226",
+		"APP_ID": "BD311",
+		"Name": "Ariel",
+		"Date": 1475332371532,
+		"Image": {"Src": "images/67.png", "Height": 450, "Width": 370}
+	}`)
+	dec := djson.NewDecoder(data)
+	dec.Only("APP_ID", "IP", "Date", "Image.Src")
+
+	event, err := dec.DecodeObject()
+	if err != nil {
+		log.Fatal("error:", err)
+	}
+
+	fmt.Printf("%v", event)
+
+	// Output:
+	// map[APP_ID:BD311 Date:1.475332371532e+12 IP:69.89.31.226 Image:map[Src:images/67.png]]
+}
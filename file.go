@@ -0,0 +1,208 @@
+package djson
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+)
+
+// DecodeFile reads the JSON-encoded file at path, and decodes it like Decode.
+// On Linux, the file is memory-mapped and decoded directly from the mapping,
+// without copying it to the heap first. Elsewhere, or if the file can not be
+// mapped(e.g. a pipe), it is read into memory.
+//
+// The mapping is released before DecodeFile returns, and the strings of the
+// result are copied out of it, so the result is safe to keep around. Note
+// that the file must not be truncated while it is decoded.
+func DecodeFile(path string) (interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, mapped, err := readFile(f)
+	if err != nil {
+		return nil, err
+	}
+	if mapped {
+		defer munmap(data)
+	}
+	return Decode(data)
+}
+
+// readFile returns the content of f. It is mapped into memory if possible,
+// and read otherwise.
+func readFile(f *os.File) (data []byte, mapped bool, err error) {
+	if data, mapped, err = mmapFile(f); err != nil || mapped {
+		return data, mapped, err
+	}
+	data, err = io.ReadAll(f)
+	return data, false, err
+}
+
+// mmapFile maps f into memory, and reports whether it succeeded. Only regular,
+// non-empty files are mapped.
+func mmapFile(f *os.File) ([]byte, bool, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	if size := fi.Size(); fi.Mode().IsRegular() && size > 0 && int64(int(size)) == size {
+		data, ok := mmap(f, int(size))
+		return data, ok, nil
+	}
+	return nil, false, nil
+}
+
+// An NDJSONFile iterates over the lines of an NDJSON file. Like DecodeFile,
+// the file is memory-mapped on Linux, and read with a buffered reader
+// elsewhere. Empty lines are skipped.
+//
+//	it, err := djson.OpenNDJSON("events.ndjson")
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		v, err := it.Decode()
+//		if err != nil {
+//			return fmt.Errorf("line %d: %w", it.Line(), err)
+//		}
+//		// use v.
+//	}
+//	return it.Err()
+//
+// The lifetime of the data that is returned by the iterator is as follows:
+//
+//   - the result of Bytes shares its memory with the mapping, so it must not
+//     be modified, and it is valid only until Close is called.
+//   - the values that are returned by Decode are copied out of the line, and
+//     they are safe to keep around, unless ZeroCopy is set on the Decoder.
+//   - with ZeroCopy, the strings share their memory with the mapping, and
+//     they are valid only until Close is called. Accessing them after that
+//     crashes the program.
+type NDJSONFile struct {
+	f      *os.File
+	d      *Decoder
+	r      *bufio.Reader
+	data   []byte
+	mapped bool
+	pos    int
+	line   []byte
+	n      int
+	eof    bool
+	err    error
+}
+
+// OpenNDJSON opens the NDJSON file at path for iteration. The caller must call
+// Close when it is done with the iterator.
+func OpenNDJSON(path string) (*NDJSONFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return newNDJSONFile(f)
+}
+
+// newNDJSONFile returns an iterator over the lines of f.
+func newNDJSONFile(f *os.File) (*NDJSONFile, error) {
+	data, mapped, err := mmapFile(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	it := &NDJSONFile{d: NewDecoder(nil), data: data, mapped: mapped}
+	if mapped {
+		// the mapping is not affected by closing the file.
+		return it, f.Close()
+	}
+	it.f, it.r = f, bufio.NewReader(f)
+	return it, nil
+}
+
+// Decoder returns the Decoder that is used by Decode, so its options can be
+// set before the iteration starts.
+func (it *NDJSONFile) Decoder() *Decoder {
+	return it.d
+}
+
+// Next advances the iterator to the next non-empty line, and reports whether
+// there is one. It returns false at the end of the file, or if an error
+// occurred while reading it.
+func (it *NDJSONFile) Next() bool {
+	for {
+		if it.mapped {
+			if it.pos == len(it.data) {
+				return false
+			}
+			rest := it.data[it.pos:]
+			i := bytes.IndexByte(rest, '\n')
+			if i < 0 {
+				i = len(rest)
+			} else {
+				i++
+			}
+			it.line = rest[:i]
+			it.pos += i
+		} else {
+			if it.eof || it.err != nil || it.r == nil {
+				return false
+			}
+			// ReadBytes returns a new slice for each line, so the strings
+			// of ZeroCopy remain valid.
+			line, err := it.r.ReadBytes('\n')
+			if err == io.EOF {
+				it.eof = true
+			} else if err != nil {
+				it.err = err
+				return false
+			}
+			if len(line) == 0 {
+				return false
+			}
+			it.line = line
+		}
+		it.n++
+		if whitespace(it.line) != len(it.line) {
+			return true
+		}
+	}
+}
+
+// Bytes returns the current line. The slice must not be modified, and it is
+// valid until Close is called.
+func (it *NDJSONFile) Bytes() []byte {
+	return it.line
+}
+
+// Line returns the number of the current line in the file, starting from 1.
+func (it *NDJSONFile) Line() int {
+	return it.n
+}
+
+// Decode decodes the current line.
+func (it *NDJSONFile) Decode() (interface{}, error) {
+	it.d.Reset(it.line)
+	return it.d.Decode()
+}
+
+// Err returns the first error that occurred while reading the file.
+func (it *NDJSONFile) Err() error {
+	return it.err
+}
+
+// Close releases the file, and the mapping if there is one. See the type
+// documentation for the lifetime of the returned data.
+func (it *NDJSONFile) Close() error {
+	it.d.Reset(nil)
+	it.line = nil
+	var err error
+	if it.mapped {
+		err = munmap(it.data)
+	} else if it.f != nil {
+		err = it.f.Close()
+	}
+	it.data, it.mapped, it.f, it.r = nil, false, nil, nil
+	return err
+}
@@ -0,0 +1,109 @@
+package djson
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, in := range []string{string(allValueIndent), `[1, "a", {"b": null}]`, `{"a": }`, ``} {
+		path := filepath.Join(dir, "file.json")
+		if err := os.WriteFile(path, []byte(in), 0644); err != nil {
+			t.Fatal(err)
+		}
+		expected, err := Decode([]byte(in))
+		val, err1 := DecodeFile(path)
+		if !reflect.DeepEqual(err, err1) {
+			t.Errorf("DecodeFile(%.20q): expecting the same error as Decode\n\tactual: %v\n\twant: %v", in, err1, err)
+		}
+		// the strings are accessed after the file was unmapped.
+		if !reflect.DeepEqual(val, expected) {
+			t.Errorf("DecodeFile(%.20q) = %v; want %v", in, val, expected)
+		}
+	}
+	if _, err := DecodeFile(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("DecodeFile: expecting a not exist error, got: %v", err)
+	}
+}
+
+func TestNDJSONFile(t *testing.T) {
+	in := "{\"a\": \"b\"}\n\n  \r\n[1, 2]\r\n{\"a\": }\n\"last\""
+	expected := []struct {
+		line int
+		val  interface{}
+		err  string
+	}{
+		{1, map[string]interface{}{"a": "b"}, ""},
+		{4, []interface{}{1.0, 2.0}, ""},
+		{5, nil, "invalid character '}' looking for beginning of value"},
+		{6, "last", ""},
+	}
+	path := filepath.Join(t.TempDir(), "file.ndjson")
+	if err := os.WriteFile(path, []byte(in), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		pw.WriteString(in)
+		pw.Close()
+	}()
+	// the pipe can not be mapped, and it is read instead.
+	piped, err := newNDJSONFile(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapped, err := OpenNDJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range []*NDJSONFile{mapped, piped} {
+		var i int
+		for ; it.Next(); i++ {
+			if i == len(expected) {
+				t.Fatalf("unexpected line %d: %q", it.Line(), it.Bytes())
+			}
+			v, err := it.Decode()
+			if it.Line() != expected[i].line || !reflect.DeepEqual(v, expected[i].val) {
+				t.Errorf("line %d = %v; want line %d = %v", it.Line(), v, expected[i].line, expected[i].val)
+			}
+			if (err == nil) != (expected[i].err == "") || err != nil && err.Error() != expected[i].err {
+				t.Errorf("line %d: unexpected error: %v", it.Line(), err)
+			}
+		}
+		if i != len(expected) || it.Err() != nil {
+			t.Errorf("expecting %d lines, got: %d (err: %v)", len(expected), i, it.Err())
+		}
+		if err := it.Close(); err != nil {
+			t.Errorf("Close: unexpected error: %v", err)
+		}
+		if it.Next() {
+			t.Error("Next: expecting false after Close")
+		}
+	}
+
+	// with ZeroCopy, the strings reference the current line.
+	it, err := OpenNDJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	it.Decoder().ZeroCopy()
+	it.Next()
+	v, _ := it.Decode()
+	if s := v.(map[string]interface{})["a"].(string); s != "b" || !strings.Contains(string(it.Bytes()), s) {
+		t.Errorf("unexpected value: %q", s)
+	}
+
+	if _, err := OpenNDJSON(path + ".missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenNDJSON: expecting a not exist error, got: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package djson
+
+import (
+	"math"
+	"math/bits"
+)
+
+// parseFloat parses the decimal number in s, that was already validated by
+// the Decoder, and returns the nearest float64 value. It reports false if the
+// value can not be computed correctly with the fast algorithms below, and the
+// caller should fall back to strconv.ParseFloat. That is, for numbers that
+// are out of the float64 range or subnormal, and for rare cases of numbers
+// with more than 19 significant digits, or that are close to the half-way
+// point of two float64 values.
+func parseFloat(s []byte) (f float64, ok bool) {
+	var (
+		i      int
+		neg    bool
+		sawdot bool
+		trunc  bool
+		nd     int
+		exp10  int
+		man    uint64
+	)
+	const (
+		// maxMant is the number of digits that fit in the mantissa, and
+		// maxExp is the bound of the exponent, as bigger exponents are out
+		// of the range anyway.
+		maxMant = 19
+		maxExp  = 1 << 16
+	)
+	if s[0] == '+' || s[0] == '-' {
+		neg = s[0] == '-'
+		i++
+	}
+
+	// the mantissa holds the first 19 significant digits, and exp10 is
+	// adjusted by the position of the decimal point and by the digits that
+	// were dropped.
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '.' {
+			sawdot = true
+			continue
+		}
+		if c < '0' || c > '9' {
+			break
+		}
+		switch {
+		case c == '0' && nd == 0:
+			// leading zeros.
+			if sawdot {
+				exp10--
+			}
+		case nd < maxMant:
+			man = 10*man + uint64(c-'0')
+			nd++
+			if sawdot {
+				exp10--
+			}
+		default:
+			trunc = trunc || c != '0'
+			if !sawdot {
+				exp10++
+			}
+		}
+	}
+
+	// e or E followed by an optional - or + and 1 or more digits.
+	if i < len(s) && s[i]|0x20 == 'e' {
+		i++
+		esign := 1
+		if s[i] == '+' || s[i] == '-' {
+			if s[i] == '-' {
+				esign = -1
+			}
+			i++
+		}
+		e := 0
+		for ; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+			if e < maxExp {
+				e = 10*e + int(s[i]-'0')
+			}
+		}
+		exp10 += esign * e
+	}
+
+	if man == 0 {
+		if neg {
+			f = math.Copysign(0, -1)
+		}
+		return f, true
+	}
+	if !trunc {
+		if f, ok = exactFloat(man, exp10, neg); ok {
+			return f, true
+		}
+	}
+	if f, ok = eiselLemire(man, exp10, neg); !ok || !trunc {
+		return f, ok
+	}
+	// the dropped digits are somewhere between man and man+1, and the
+	// result is correct only if both of them round to the same value.
+	if f1, ok := eiselLemire(man+1, exp10, neg); !ok || f1 != f {
+		return 0, false
+	}
+	return f, true
+}
+
+// exactPowersOfTen are the powers of 10 that can be represented exactly in
+// a float64.
+var exactPowersOfTen = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19,
+	1e20, 1e21, 1e22,
+}
+
+// exactFloat computes man*10^exp10 with a single float64 multiplication or
+// division, when both of its operands are exact, and therefore its result
+// is correctly rounded. The idea comes from Clinger's "How to Read Floating
+// Point Numbers Accurately".
+//
+// This is the same fast path as atof64exact in the Go standard library,
+// strconv/atof.go(Copyright 2009 The Go Authors, BSD-style license, see the
+// LICENSE file of the Go source tree), and so is the table of exact powers.
+func exactFloat(man uint64, exp10 int, neg bool) (f float64, ok bool) {
+	if man>>53 != 0 {
+		return 0, false
+	}
+	f = float64(man)
+	if neg {
+		f = -f
+	}
+	switch {
+	case exp10 == 0:
+		return f, true
+	case exp10 > 0 && exp10 <= 15+22:
+		// the mantissa can absorb some of the zeros, if it is small enough.
+		if exp10 > 22 {
+			f *= exactPowersOfTen[exp10-22]
+			exp10 = 22
+		}
+		if f > 1e15 || f < -1e15 {
+			return 0, false
+		}
+		return f * exactPowersOfTen[exp10], true
+	case exp10 < 0 && exp10 >= -22:
+		return f / exactPowersOfTen[-exp10], true
+	}
+	return 0, false
+}
+
+// eiselLemire computes man*10^exp10 using the Eisel-Lemire algorithm, as
+// described in Daniel Lemire's "Number Parsing at a Gigabyte per Second".
+// It reports false if the result can not be determined(i.e. it is ambiguous,
+// out of the range or subnormal).
+//
+// Most of the code in this function was copied from the Go standard library,
+// strconv/eisel_lemire.go(eiselLemire64), which is Copyright 2020 The Go
+// Authors, and is governed by a BSD-style license that can be found in the
+// LICENSE file of the Go source tree.
+func eiselLemire(man uint64, exp10 int, neg bool) (f float64, ok bool) {
+	if exp10 < minExp10 || exp10 > maxExp10 {
+		return 0, false
+	}
+
+	// normalize the mantissa, and compute the binary exponent of the result;
+	// 217706/2^16 is an approximation of log2(10).
+	clz := bits.LeadingZeros64(man)
+	man <<= uint(clz)
+	const bias = 1023
+	exp2 := uint64(217706*exp10>>16+64+bias) - uint64(clz)
+
+	// multiply the mantissa by the 128-bit approximation of 10^exp10.
+	pow := &powersOfTen[exp10-minExp10]
+	hi, lo := bits.Mul64(man, pow[1])
+	if hi&0x1FF == 0x1FF && lo+man < man {
+		// the lower bits of the power may carry into the result.
+		yhi, ylo := bits.Mul64(man, pow[0])
+		mhi, mlo := hi, lo+yhi
+		if mlo < lo {
+			mhi++
+		}
+		if mhi&0x1FF == 0x1FF && mlo+1 == 0 && ylo+man < man {
+			return 0, false
+		}
+		hi, lo = mhi, mlo
+	}
+
+	// shift the result to 54 bits.
+	top := hi >> 63
+	mant := hi >> (top + 9)
+	exp2 -= 1 ^ top
+
+	// the result is exactly half-way between two float64 values, and the
+	// approximation can not tell how it should be rounded.
+	if lo == 0 && hi&0x1FF == 0 && mant&3 == 1 {
+		return 0, false
+	}
+
+	// round to 53 bits, and handle the overflow of the rounding.
+	mant += mant & 1
+	mant >>= 1
+	if mant>>53 > 0 {
+		mant >>= 1
+		exp2++
+	}
+	// zero or underflow of exp2 means that the result is subnormal, and
+	// 0x7FF or above means that it is out of the float64 range.
+	if exp2-1 >= 0x7FF-1 {
+		return 0, false
+	}
+	b := exp2<<52 | mant&(1<<52-1)
+	if neg {
+		b |= 1 << 63
+	}
+	return math.Float64frombits(b), true
+}
@@ -0,0 +1,84 @@
+//go:build ignore
+
+// This program generates float_table.go. Run it with:
+//
+//	go run float_gen.go
+//
+// The table has the same layout as detailedPowersOfTen in the Go standard
+// library, strconv/eisel_lemire.go, which is Copyright 2020 The Go Authors,
+// and is governed by a BSD-style license that can be found in the LICENSE
+// file of the Go source tree.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"math/big"
+	"os"
+)
+
+const (
+	minExp10 = -348
+	maxExp10 = 347
+)
+
+func main() {
+	var (
+		one    = big.NewInt(1)
+		ten    = big.NewInt(10)
+		b1p64  = new(big.Int).Lsh(one, 64)
+		b1p127 = new(big.Int).Lsh(one, 127)
+		b1p128 = new(big.Int).Lsh(one, 128)
+	)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, header, minExp10, maxExp10)
+	for e := int64(minExp10); e <= maxExp10; e++ {
+		// scale 10^e by a power of 2, so it is in the range [2^127, 2^128),
+		// and round it down.
+		var m *big.Int
+		if e >= 0 {
+			m = new(big.Int).Exp(ten, big.NewInt(e), nil)
+			for m.Cmp(b1p128) >= 0 {
+				m.Rsh(m, 1)
+			}
+			for m.Cmp(b1p127) < 0 {
+				m.Lsh(m, 1)
+			}
+		} else {
+			d := new(big.Int).Exp(ten, big.NewInt(-e), nil)
+			m = new(big.Int).Lsh(one, uint(127+d.BitLen()))
+			if m.Div(m, d); m.Cmp(b1p128) >= 0 {
+				m.Rsh(m, 1)
+			}
+		}
+		hi, lo := new(big.Int).DivMod(m, b1p64, new(big.Int))
+		fmt.Fprintf(&out, "\t{%#016x, %#016x}, // 1e%d\n", lo.Uint64(), hi.Uint64(), e)
+	}
+	fmt.Fprintf(&out, "}\n")
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("float_table.go", src, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+const header = `// Code generated by "go run float_gen.go"; DO NOT EDIT.
+
+package djson
+
+const (
+	minExp10 = %d
+	maxExp10 = %d
+)
+
+// powersOfTen holds the 128-bit mantissas of the powers of 10 in the range
+// [minExp10, maxExp10], rounded down. The mantissas are scaled so the high
+// bit is always set, and each of them is stored as {lo, hi}.
+var powersOfTen = [...][2]uint64{
+`
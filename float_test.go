@@ -0,0 +1,112 @@
+package djson
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var floatTests = []string{
+	"0", "1", "0.1", "0.3", "1.5", "123.456", "1e-3", "1E+3", "2.0e2", "0.000001234",
+	"9007199254740992", "9007199254740993", "9007199254740995", "18446744073709551615",
+	"18446744073709551616", "123456789012345678901234567890",
+	"0.1000000000000000055511151231257827", "1.00000000000000011102230246251565404236316680908203125",
+	"1.00000000000000011102230246251565404236316680908203124",
+	"1.00000000000000011102230246251565404236316680908203126",
+	"2.2250738585072011e-308", "2.2250738585072014e-308", "4.9e-324", "5e-324", "1e-400",
+	"1.7976931348623157e308", "1.7976931348623158e308", "1.7976931348623159e308", "1e309",
+	"7.3177701707893310e+15", "5.8339553793802237e+23", "9.5162545882716700e-108",
+	"1e22", "1e23", "8.98846567431158e307", "3.4028234663852886e38", "1e-22", "1e-23",
+	"100000000000000016777215", "100000000000000016777216", "4.35679e-320",
+	"0.0000000000000000000000000000000000000000001", "12345678901234567890e-30",
+	"1e0", "0e1000", "0.0e-1000",
+}
+
+func TestParseFloat(t *testing.T) {
+	check := func(s string) {
+		want, err := strconv.ParseFloat(s, 64)
+		f, ok := parseFloat([]byte(s))
+		if !ok {
+			return
+		}
+		if err != nil {
+			t.Errorf("parseFloat(%s) = %v; want error %v", s, f, err)
+		} else if math.Float64bits(f) != math.Float64bits(want) {
+			t.Errorf("parseFloat(%s) = %v; want %v", s, f, want)
+		}
+	}
+	for _, s := range floatTests {
+		check(s)
+		check("-" + s)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		f := math.Float64frombits(rnd.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		// the shortest representation, and others with more digits.
+		check(strconv.FormatFloat(f, 'g', -1, 64))
+		check(strconv.FormatFloat(f, 'e', rnd.Intn(25), 64))
+		check(strconv.FormatFloat(math.Abs(f), 'f', -1, 64))
+	}
+	// the fast paths handle the common cases.
+	for _, s := range []string{"0.1", "123.456", "1e-3", "5.8339553793802237e+23", "12345678901234567890e-30"} {
+		if _, ok := parseFloat([]byte(s)); !ok {
+			t.Errorf("parseFloat(%s): expecting the fast path to succeed", s)
+		}
+	}
+}
+
+func TestNumberAllocs(t *testing.T) {
+	data := []byte(`[1.5, -2.25e3, 0.001, 123456.789, 12345678901234567890]`)
+	d := NewDecoder(data)
+	d.Arena()
+	d.DecodeArray()
+	allocs := testing.AllocsPerRun(100, func() {
+		d.Reset(data)
+		d.pos++
+		for c := d.skipSpaces(); c != ']'; c = d.skipSpaces() {
+			if c == '-' {
+				d.pos++
+			}
+			if _, err := d.number(); err != nil {
+				t.Fatal(err)
+			}
+			if d.skipSpaces() == ',' {
+				d.pos++
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expecting number to not allocate, got: %v", allocs)
+	}
+}
+
+func FuzzParseFloat(f *testing.F) {
+	for _, s := range floatTests {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		// only valid JSON numbers are passed to parseFloat.
+		d := NewDecoder([]byte(s))
+		d.Strict()
+		v, err := d.Decode()
+		if err != nil {
+			return
+		}
+		n, ok := v.(float64)
+		if !ok {
+			return
+		}
+		want, err := strconv.ParseFloat(strings.Trim(s, " \t\n\r"), 64)
+		if err != nil {
+			t.Fatalf("Decode(%s) = %v; want error %v", s, n, err)
+		}
+		if math.Float64bits(n) != math.Float64bits(want) {
+			t.Fatalf("Decode(%s) = %v; want %v", s, n, want)
+		}
+	})
+}
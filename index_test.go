@@ -0,0 +1,113 @@
+package djson
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStructuralIndex(t *testing.T) {
+	inputs := []string{
+		string(allValueIndent),
+		`{"a\\": "\\\\", "b\\\"": ["\"", "\\\\\""], "c": {"d": [[], {}]}}`,
+		`["` + strings.Repeat(`\\`, 40) + `", "` + strings.Repeat(`\"`, 40) + `", 1]`,
+		`[1,2,"` + strings.Repeat(`a`, 100) + `"]` + strings.Repeat(" ", 100),
+		`{"a": truex}`, `["a"x]`, `{"a" 1}`, `[1 2]`, `[1,]`, `{"a":1,}`, `[1}`, `{]`, `]`, `"a`,
+		`[-1, 0.5e3, true, false, null, "x"]`, `{"a": -, "b": 1}`, `[1]]`, `{} {}`,
+	}
+	// escapes, non-ASCII and control characters around the boundaries of
+	// the 64-byte blocks.
+	for i := 0; i < 140; i += 3 {
+		pad := strings.Repeat("a", i)
+		inputs = append(inputs,
+			`["`+pad+`\"", "`+pad+`"]`,
+			`{"`+pad+`": "é`+pad+`", "b": "`+pad+`\\"}`,
+			`["`+pad+`", "x`+pad+"\t"+`"]`,
+			`[`+pad[:i/2]+`"`+pad+`\\\"`+pad+`"]`,
+		)
+	}
+	for _, tt := range decodeTests {
+		inputs = append(inputs, tt.in)
+	}
+	files, _ := filepath.Glob("testdata/JSONTestSuite/test_parsing/*.json")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, string(data))
+	}
+	for _, in := range inputs {
+		expected, err := NewDecoder([]byte(in)).Decode()
+		// the index is used directly, as the inputs are below its threshold.
+		d := NewDecoder([]byte(in))
+		val, ok := d.decodeIndexed()
+		if ok != (err == nil) {
+			t.Errorf("decodeIndexed(%q): ok = %v, want %v (err: %v)", in, ok, err == nil, err)
+			continue
+		}
+		if ok && !reflect.DeepEqual(val, expected) {
+			t.Errorf("decodeIndexed(%q) = %v; want %v", in, val, expected)
+		}
+		if !ok && d.pos != 0 {
+			t.Errorf("decodeIndexed(%q): expecting the position to be reset, got: %d", in, d.pos)
+		}
+	}
+}
+
+func TestStructuralIndexDecoder(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for b.Len() < indexThreshold {
+		b.WriteString("\n\t")
+		b.Write(allValueIndent)
+		b.WriteString(",")
+	}
+	b.WriteString(` {"last": [1, 2, 3]}]`)
+	valid := b.String()
+
+	d := NewDecoder(nil)
+	d.StructuralIndex()
+	d.InternKeys(100)
+	for _, in := range []string{
+		valid,
+		valid + " x",
+		valid[:len(valid)-1],
+		strings.Replace(valid, `"last"`, `"last`, 1),
+		strings.Replace(valid, `[1, 2, 3]`, `[1, 2, 3x]`, 1),
+	} {
+		expected, err := Decode([]byte(in))
+		d.Reset([]byte(in))
+		val, err1 := d.Decode()
+		if !reflect.DeepEqual(err, err1) {
+			t.Errorf("expecting the same error as Decode\n\tactual: %v\n\twant: %v", err1, err)
+		}
+		if !reflect.DeepEqual(val, expected) {
+			t.Error("expecting the same value as Decode")
+		}
+		d.Reset([]byte(in))
+		array, err1 := d.DecodeArray()
+		if !reflect.DeepEqual(err, err1) {
+			t.Errorf("DecodeArray: expecting the same error as Decode\n\tactual: %v\n\twant: %v", err1, err)
+		}
+		if err == nil && !reflect.DeepEqual(array, expected) {
+			t.Error("DecodeArray: expecting the same value as Decode")
+		}
+	}
+
+	// type mismatch is reported by the recursive descent parser.
+	d.Reset([]byte(valid))
+	if _, err := d.DecodeObject(); err == nil || err.Error() != "invalid character '[' looking for beginning of object" {
+		t.Errorf("DecodeObject: unexpected error: %v", err)
+	}
+
+	// arrays and objects are pre-sized.
+	d.Reset([]byte(valid))
+	val, _ := d.DecodeArray()
+	last := val[len(val)-1].(map[string]interface{})["last"].([]interface{})
+	if cap(last) != 3 {
+		t.Errorf("cap(last) = %d; want 3", cap(last))
+	}
+}
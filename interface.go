@@ -1,5 +1,7 @@
 package djson
 
+import "sync"
+
 // A SyntaxError is a description of a JSON syntax error.
 type SyntaxError struct {
 	msg    string // description of error
@@ -62,6 +64,35 @@ func Type(v interface{}) ValueType {
 	return t
 }
 
+// decoders is a pool of Decoders that is used by the package-level functions
+// below, in order to reuse their internal buffers between calls. The pooled
+// Decoders do not intern object keys, since they are shared by unrelated
+// documents, and an intern table would keep their keys alive.
+var decoders = sync.Pool{
+	New: func() interface{} {
+		return new(Decoder)
+	},
+}
+
+// maxScratch is the maximum size of a scratch space that is kept in a pooled
+// Decoder, in order to not hold big buffers for too long.
+const maxScratch = 64 << 10
+
+func getDecoder(data []byte) *Decoder {
+	d := decoders.Get().(*Decoder)
+	d.Reset(data)
+	return d
+}
+
+func putDecoder(d *Decoder) {
+	// release the data, so it can be collected.
+	d.Reset(nil)
+	if cap(d.scratch) > maxScratch {
+		d.scratch = nil
+	}
+	decoders.Put(d)
+}
+
 // Decode parses the JSON-encoded data and returns an interface value.
 // The interface value could be one of these:
 //
@@ -77,53 +108,32 @@ func Type(v interface{}) ValueType {
 //
 //	var v interface{}
 //	err := json.Unmarshal(data, &v)
-//
 func Decode(data []byte) (interface{}, error) {
-	d := NewDecoder(data)
-	val, err := d.any()
-	if err != nil {
-		return nil, err
-	}
-	if c := d.skipSpaces(); d.pos < d.end {
-		return nil, d.error(c, "after top-level value")
-	}
-	return val, nil
+	d := getDecoder(data)
+	defer putDecoder(d)
+	return d.Decode()
 }
 
 // DecodeObject is the same as Decode but it returns map[string]interface{}.
 // You should use it to parse JSON objects.
 func DecodeObject(data []byte) (map[string]interface{}, error) {
-	d := NewDecoder(data)
-	if c := d.skipSpaces(); c != '{' {
-		return nil, d.error(c, "looking for beginning of object")
-	}
-	val, err := d.object()
-	if err != nil {
-		return nil, err
-	}
-	if c := d.skipSpaces(); d.pos < d.end {
-		return nil, d.error(c, "after top-level value")
-	}
-	return val, nil
+	d := getDecoder(data)
+	defer putDecoder(d)
+	return d.DecodeObject()
 }
 
 // DecodeArray is the same as Decode but it returns []interface{}.
 // You should use it to parse JSON arrays.
 func DecodeArray(data []byte) ([]interface{}, error) {
-	d := NewDecoder(data)
-	if c := d.skipSpaces(); c != '[' {
-		return nil, d.error(c, "looking for beginning of array")
-	}
-	val, err := d.array()
-	if err != nil {
-		return nil, err
-	}
-	if c := d.skipSpaces(); d.pos < d.end {
-		return nil, d.error(c, "after top-level value")
-	}
-	return val, nil
+	d := getDecoder(data)
+	defer putDecoder(d)
+	return d.DecodeArray()
 }
 
-// TODO(a8m): the 3 methods above could be written like this:
-//
-// 	return NewDecoder(data).DecodeXXX()
+// DecodeLenient is the same as Decode but it keeps decoding after syntax
+// errors. See Decoder.DecodeLenient for more details.
+func DecodeLenient(data []byte) (interface{}, []*SyntaxError) {
+	d := getDecoder(data)
+	defer putDecoder(d)
+	return d.DecodeLenient()
+}
@@ -0,0 +1,356 @@
+package djson
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSON5 makes the Decoder accept JSON5(https://json5.org) input, which is
+// commonly used for hand-edited configuration files. In addition to the
+// standard JSON syntax, it accepts:
+//
+//   - unquoted identifier keys, and single-quoted strings
+//   - trailing commas in arrays and objects
+//   - hexadecimal numbers, leading or trailing decimal points and a leading + sign
+//   - Infinity, -Infinity and NaN
+//   - strings that span multiple lines using a backslash continuation, and
+//     the additional escapes \v, \0, \xHH and escaped non-escape characters
+//   - line and block comments, and additional white space characters
+//
+// The values returned by the Decoder are the same types as in Decode.
+func (d *Decoder) JSON5() {
+	d.json5 = true
+	d.comments = true
+	d.trailing = true
+	d.nan = true
+}
+
+// space5 skips the JSON5 white space character at the current position, and
+// reports whether it succeeded. It is called only in JSON5 mode.
+func (d *Decoder) space5() bool {
+	c := d.data[d.pos]
+	if c == '\v' || c == '\f' {
+		d.pos++
+		return true
+	}
+	r, size := utf8.DecodeRune(d.data[d.pos:d.end])
+	if r == '\u2028' || r == '\u2029' || r == '\uFEFF' || unicode.Is(unicode.Zs, r) {
+		d.pos += size
+		return true
+	}
+	return false
+}
+
+// key5 called by `object` to read a JSON5 object key; a quoted string or an
+// identifier name.
+func (d *Decoder) key5(c byte) (string, error) {
+	if c == '"' || c == '\'' {
+		return d.string5(c)
+	}
+	if c >= utf8.RuneSelf {
+		if r, _ := utf8.DecodeRune(d.data[d.pos:d.end]); isIdentStart(r) {
+			return d.identifier()
+		}
+	} else if c == '\\' || isIdentStart(rune(c)) {
+		return d.identifier()
+	}
+	return "", d.error(c, "looking for beginning of object key string")
+}
+
+// identifier reads an ECMAScript identifier name that is used as an
+// unquoted object key.
+func (d *Decoder) identifier() (string, error) {
+	var (
+		buf     []byte
+		escaped bool
+		start   = d.pos
+	)
+	for d.pos < d.end {
+		var (
+			r    rune
+			size int
+			c    = d.data[d.pos]
+		)
+		switch {
+		case c == '\\':
+			if d.end-d.pos < 6 {
+				return "", ErrUnexpectedEOF
+			}
+			if r = getu4(d.data[d.pos : d.pos+6]); r < 0 {
+				return "", d.error(c, "in identifier escape")
+			}
+			if !escaped {
+				escaped = true
+				buf = append(buf, d.data[start:d.pos]...)
+			}
+			size = 6
+		case c < utf8.RuneSelf:
+			r, size = rune(c), 1
+		default:
+			r, size = utf8.DecodeRune(d.data[d.pos:d.end])
+		}
+		if d.pos == start && !isIdentStart(r) || !isIdentPart(r) {
+			if d.pos == start || c == '\\' {
+				return "", d.error(c, "in identifier")
+			}
+			break
+		}
+		if escaped {
+			buf = utf8.AppendRune(buf, r)
+		}
+		d.pos += size
+	}
+	if escaped {
+		return string(buf), nil
+	}
+	return d.slice(start, d.pos), nil
+}
+
+func isIdentStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '_' || r == '$'
+	}
+	return unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isIdentPart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isIdentStart(r) || '0' <= r && r <= '9'
+	}
+	return isIdentStart(r) || r == '\u200C' || r == '\u200D' || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)
+}
+
+// string5 called by `any` or `object` to read a JSON5 string that is
+// delimited by the given quote character.
+func (d *Decoder) string5(quote byte) (string, error) {
+	d.pos++
+	start := d.pos
+
+	// fast path for strings that have no escapes, non-ASCII characters and
+	// line terminators.
+	for ; d.pos < d.end; d.pos++ {
+		c := d.data[d.pos]
+		if c == quote {
+			d.pos++
+			return d.slice(start, d.pos-1), nil
+		}
+		if c == '\\' || c == '\n' || c == '\r' || c >= utf8.RuneSelf {
+			break
+		}
+	}
+
+	var stackbuf [64]byte
+	buf := append(stackbuf[:0], d.data[start:d.pos]...)
+	for d.pos < d.end {
+		switch c := d.data[d.pos]; {
+		case c == quote:
+			d.pos++
+			return string(buf), nil
+		case c == '\n', c == '\r':
+			return "", d.error(c, "in string literal")
+		case c == '\\':
+			d.pos++
+			if d.pos >= d.end {
+				return "", ErrUnexpectedEOF
+			}
+			var err error
+			if buf, err = d.escape5(buf); err != nil {
+				return "", err
+			}
+		case c < utf8.RuneSelf:
+			buf = append(buf, c)
+			d.pos++
+		default:
+			r, size := utf8.DecodeRune(d.data[d.pos:d.end])
+			if r == utf8.RuneError && size == 1 {
+				switch d.utf8 {
+				case UTF8Reject:
+					return "", &SyntaxError{"invalid UTF-8 in string literal", d.pos + 1}
+				case UTF8Pass:
+					buf = append(buf, c)
+					d.pos++
+					continue
+				}
+			}
+			buf = utf8.AppendRune(buf, r)
+			d.pos += size
+		}
+	}
+	return "", ErrUnexpectedEOF
+}
+
+// escape5 decodes the escape sequence that starts after the backslash at the
+// current position, and appends it to the given buffer.
+func (d *Decoder) escape5(buf []byte) ([]byte, error) {
+	c := d.data[d.pos]
+	d.pos++
+	switch c {
+	case 'b':
+		return append(buf, '\b'), nil
+	case 'f':
+		return append(buf, '\f'), nil
+	case 'n':
+		return append(buf, '\n'), nil
+	case 'r':
+		return append(buf, '\r'), nil
+	case 't':
+		return append(buf, '\t'), nil
+	case 'v':
+		return append(buf, '\v'), nil
+	case '0':
+		if d.pos < d.end && '0' <= d.data[d.pos] && d.data[d.pos] <= '9' {
+			return nil, d.error(d.data[d.pos], "in string escape code")
+		}
+		return append(buf, 0), nil
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		d.pos--
+		return nil, d.error(c, "in string escape code")
+	case '\n':
+		// line continuation; a CRLF sequence is a single line terminator.
+		return buf, nil
+	case '\r':
+		if d.pos < d.end && d.data[d.pos] == '\n' {
+			d.pos++
+		}
+		return buf, nil
+	case 'x':
+		if d.end-d.pos < 2 {
+			return nil, ErrUnexpectedEOF
+		}
+		r := unhex(d.data[d.pos : d.pos+2])
+		if r < 0 {
+			return nil, d.error(d.data[d.pos], "in \\x hexadecimal character escape")
+		}
+		d.pos += 2
+		return utf8.AppendRune(buf, r), nil
+	case 'u':
+		start := d.pos - 2
+		if d.end-d.pos < 4 {
+			return nil, ErrUnexpectedEOF
+		}
+		r := unhex(d.data[d.pos : d.pos+4])
+		if r < 0 {
+			return nil, d.error(d.data[d.pos], "in \\u hexadecimal character escape")
+		}
+		d.pos += 4
+		if utf16.IsSurrogate(r) {
+			// a valid surrogate pair is combined, otherwise fall back to
+			// the replacement rune like Decode does.
+			if r1 := getu4(d.data[d.pos:d.end]); r < 0xDC00 && r1 >= 0xDC00 && r1 < 0xE000 {
+				d.pos += 6
+				r = utf16.DecodeRune(r, r1)
+			} else if d.utf8 == UTF8Reject {
+				return nil, &SyntaxError{"invalid lone surrogate " + string(d.data[start:start+6]) + " in string literal", start + 1}
+			} else {
+				r = unicode.ReplacementChar
+			}
+		}
+		return utf8.AppendRune(buf, r), nil
+	}
+	if c < utf8.RuneSelf {
+		return append(buf, c), nil
+	}
+	// an escaped non-ASCII character stands for itself, except for the
+	// line terminators that are used as line continuations.
+	d.pos--
+	r, size := utf8.DecodeRune(d.data[d.pos:d.end])
+	d.pos += size
+	if r == '\u2028' || r == '\u2029' {
+		return buf, nil
+	}
+	return utf8.AppendRune(buf, r), nil
+}
+
+// unhex decodes the hexadecimal digits in s, or it returns -1.
+func unhex(s []byte) rune {
+	var r rune
+	for _, c := range s {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1
+		}
+		r = r<<4 | rune(c)
+	}
+	return r
+}
+
+// number5 called by `any` to read a JSON5 number, including its sign.
+func (d *Decoder) number5() (float64, error) {
+	var (
+		neg    bool
+		digits int
+		start  = d.pos
+		c      = d.data[d.pos]
+	)
+	if c == '+' || c == '-' {
+		neg = c == '-'
+		if c = d.next(); d.pos >= d.end {
+			return 0, ErrUnexpectedEOF
+		}
+	}
+
+	switch {
+	case c == 'I' || c == 'N':
+		return d.nonFinite(neg)
+	case c == '0' && d.pos+1 < d.end && d.data[d.pos+1]|0x20 == 'x':
+		var n float64
+		d.pos++
+		for c = d.next(); ; c = d.next() {
+			v := unhex([]byte{c})
+			if v < 0 {
+				break
+			}
+			n = 16*n + float64(v)
+			digits++
+		}
+		if digits == 0 {
+			return 0, d.error(c, "in hexadecimal numeric literal")
+		}
+		if neg {
+			n = -n
+		}
+		return n, nil
+	}
+
+	// integer part; leading zeros are not allowed.
+	if c == '0' {
+		c = d.next()
+		digits++
+	} else {
+		for ; '0' <= c && c <= '9'; c = d.next() {
+			digits++
+		}
+	}
+	// . followed by 0 or more digits, or preceded by at least one digit.
+	if c == '.' {
+		for c = d.next(); '0' <= c && c <= '9'; c = d.next() {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, d.error(c, "in numeric literal")
+	}
+	// e or E followed by an optional - or + and 1 or more digits.
+	if c == 'e' || c == 'E' {
+		if c = d.next(); c == '+' || c == '-' {
+			c = d.next()
+		}
+		if c < '0' || c > '9' {
+			return 0, d.error(c, "in exponent of numeric literal")
+		}
+		for ; '0' <= c && c <= '9'; c = d.next() {
+		}
+	}
+	if f, ok := parseFloat(d.data[start:d.pos]); ok {
+		return f, nil
+	}
+	return strconv.ParseFloat(string(d.data[start:d.pos]), 64)
+}
@@ -0,0 +1,121 @@
+package djson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestJSON5(t *testing.T) {
+	for i, tt := range []decodeTest{
+		// objects
+		{in: `{a: 1, $b: 2, _c: 3, d4: 4}`, expected: map[string]interface{}{"a": 1.0, "$b": 2.0, "_c": 3.0, "d4": 4.0}},
+		{in: `{'a': 'b', "c": "d",}`, expected: map[string]interface{}{"a": "b", "c": "d"}},
+		{in: `{ünicode: 1, ab: 2}`, expected: map[string]interface{}{"ünicode": 1.0, "ab": 2.0}},
+		{in: `{null: null, true: true}`, expected: map[string]interface{}{"null": nil, "true": true}},
+		{in: `{a: {b: [1,],},}`, expected: map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1.0}}}},
+
+		// arrays
+		{in: `[1, 2,]`, expected: []interface{}{1.0, 2.0}},
+		{in: `[1, 2, ]`, expected: []interface{}{1.0, 2.0}},
+
+		// strings
+		{in: `'a"b'`, expected: `a"b`},
+		{in: `"a'b"`, expected: `a'b`},
+		{in: `'a\'b'`, expected: `a'b`},
+		{in: `'\v\0\x41\Aé𝄞'`, expected: "\v\x00A" + "Aé\U0001D11E"},
+		{in: "'line 1 \\\nline 2 \\\r\nline 3 \\\rline 4 \\ line 5'", expected: "line 1 line 2 line 3 line 4 line 5"},
+		{in: "'tab\there,  '", expected: "tab\there,  "},
+		{in: "'déjà vu'", expected: "déjà vu"},
+
+		// numbers
+		{in: `0x1F`, expected: 31.0},
+		{in: `-0XaBc`, expected: -2748.0},
+		{in: `+1`, expected: 1.0},
+		{in: `.5`, expected: 0.5},
+		{in: `5.`, expected: 5.0},
+		{in: `-.5e1`, expected: -5.0},
+		{in: `+5.e-1`, expected: 0.5},
+		{in: `Infinity`, expected: math.Inf(1)},
+		{in: `-Infinity`, expected: math.Inf(-1)},
+		{in: `+Infinity`, expected: math.Inf(1)},
+
+		// comments and white spaces
+		{in: "// comment\n{a /* b */: 1}", expected: map[string]interface{}{"a": 1.0}},
+		{in: "\uFEFF\v\f \u00A0 \u3000[1]", expected: []interface{}{1.0}},
+
+		// errors
+		{in: `{1a: 1}`, err: &SyntaxError{"invalid character '1' looking for beginning of object key string", 2}},
+		{in: `{a-b: 1}`, err: &SyntaxError{"invalid character '-' after object key", 3}},
+		{in: `{\u0031: 1}`, err: &SyntaxError{"invalid character '\\\\' in identifier", 2}},
+		{in: `{\u0061\u0062c: 1}`, expected: map[string]interface{}{"abc": 1.0}},
+		{in: `[1,,]`, err: &SyntaxError{"invalid character ',' looking for beginning of value", 4}},
+		{in: `{,}`, err: &SyntaxError{"invalid character ',' looking for beginning of object key string", 2}},
+		{in: "'a\nb'", err: &SyntaxError{"invalid character '\\n' in string literal", 3}},
+		{in: `'\1'`, err: &SyntaxError{"invalid character '1' in string escape code", 3}},
+		{in: `'\01'`, err: &SyntaxError{"invalid character '1' in string escape code", 4}},
+		{in: `'\xZZ'`, err: &SyntaxError{"invalid character 'Z' in \\x hexadecimal character escape", 4}},
+		{in: `0x`, err: ErrUnexpectedEOF},
+		{in: `0xg`, err: &SyntaxError{"invalid character 'g' in hexadecimal numeric literal", 3}},
+		{in: `.`, err: ErrUnexpectedEOF},
+		{in: `.e1`, err: &SyntaxError{"invalid character 'e' in numeric literal", 2}},
+		{in: `1e`, err: ErrUnexpectedEOF},
+		{in: `01`, err: &SyntaxError{"invalid character '1' after top-level value", 2}},
+		{in: `Infinit`, err: ErrUnexpectedEOF},
+		{in: `-Infinty`, err: &SyntaxError{"invalid character 't' in literal Infinity", 7}},
+		{in: `'abc`, err: ErrUnexpectedEOF},
+	} {
+		d := NewDecoder([]byte(tt.in))
+		d.JSON5()
+		out, err := d.Decode()
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: %v, want %v", i, err, tt.err)
+		}
+		if !reflect.DeepEqual(out, tt.expected) {
+			t.Errorf("#%d: %q, want %q", i, out, tt.expected)
+		}
+	}
+
+	// NaN is not equal to itself
+	d := NewDecoder([]byte(`[NaN, -NaN]`))
+	d.JSON5()
+	out, err := d.DecodeArray()
+	if err != nil {
+		t.Fatalf("expecting NaN not to fail: %q", err)
+	}
+	for _, v := range out {
+		if f, ok := v.(float64); !ok || !math.IsNaN(f) {
+			t.Errorf("%v, want NaN", v)
+		}
+	}
+}
+
+func TestJSON5CompatibleWithDecode(t *testing.T) {
+	expected, err := Decode(allValueIndent)
+	if err != nil {
+		t.Fatalf("expecting decode not to fail: %q", err)
+	}
+	d := NewDecoder(allValueIndent)
+	d.JSON5()
+	actual, err := d.Decode()
+	if err != nil {
+		t.Fatalf("expecting JSON5 decode not to fail: %q", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("compare to Decode \n\tactual: %v\n\twant: %v", actual, expected)
+	}
+	for i, tt := range decodeTests {
+		if tt.err != nil {
+			continue
+		}
+		d := NewDecoder([]byte(tt.in))
+		d.JSON5()
+		out, err := d.Decode()
+		if err != nil {
+			t.Errorf("#%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(out, tt.expected) {
+			t.Errorf("#%d: %v, want %v", i, out, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package djson
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The test_parsing corpus of the JSONTestSuite(https://github.com/nst/JSONTestSuite)
+// is vendored in testdata/JSONTestSuite. Files prefixed with y_ must be
+// accepted, files prefixed with n_ must be rejected, and files prefixed
+// with i_ are left up to the implementation by RFC 8259.
+
+// implementationDefined documents the behavior for the i_ files. The
+// two fields report whether the input is accepted by the default Decoder
+// and by a Decoder in Strict mode.
+var implementationDefined = map[string]struct{ decode, strict bool }{
+	// numbers that underflow are rounded to zero, big integers lose their
+	// precision, and numbers out of the float64 range are rejected.
+	"i_number_double_huge_neg_exp.json":   {true, true},
+	"i_number_huge_exp.json":              {false, false},
+	"i_number_neg_int_huge_exp.json":      {false, false},
+	"i_number_pos_double_huge_exp.json":   {false, false},
+	"i_number_real_neg_overflow.json":     {false, false},
+	"i_number_real_pos_overflow.json":     {false, false},
+	"i_number_real_underflow.json":        {true, true},
+	"i_number_too_big_neg_int.json":       {true, true},
+	"i_number_too_big_pos_int.json":       {true, true},
+	"i_number_very_big_negative_int.json": {true, true},
+
+	// lone or invalid surrogates in \u escapes are replaced with U+FFFD, and
+	// rejected in Strict mode(see UTF8Reject).
+	"i_object_key_lone_2nd_surrogate.json":                {true, false},
+	"i_string_1st_surrogate_but_2nd_missing.json":         {true, false},
+	"i_string_1st_valid_surrogate_2nd_invalid.json":       {true, false},
+	"i_string_incomplete_surrogate_and_escape_valid.json": {true, false},
+	"i_string_incomplete_surrogate_pair.json":             {true, false},
+	"i_string_incomplete_surrogates_escape_valid.json":    {true, false},
+	"i_string_invalid_lonely_surrogate.json":              {true, false},
+	"i_string_invalid_surrogate.json":                     {true, false},
+	"i_string_inverted_surrogates_U+1D11E.json":           {true, false},
+	"i_string_lone_second_surrogate.json":                 {true, false},
+
+	// invalid UTF-8 is coerced to valid UTF-8 by replacing it with U+FFFD,
+	// and rejected in Strict mode.
+	"i_string_UTF-8_invalid_sequence.json":         {true, false},
+	"i_string_UTF8_surrogate_U+D800.json":          {true, false},
+	"i_string_invalid_utf-8.json":                  {true, false},
+	"i_string_iso_latin_1.json":                    {true, false},
+	"i_string_lone_utf8_continuation_byte.json":    {true, false},
+	"i_string_not_in_unicode_range.json":           {true, false},
+	"i_string_overlong_sequence_2_bytes.json":      {true, false},
+	"i_string_overlong_sequence_6_bytes.json":      {true, false},
+	"i_string_overlong_sequence_6_bytes_null.json": {true, false},
+	"i_string_truncated-utf-8.json":                {true, false},
+
+	// only UTF-8 input without a BOM is supported.
+	"i_string_UTF-16LE_with_BOM.json":         {false, false},
+	"i_string_utf16BE_no_BOM.json":            {false, false},
+	"i_string_utf16LE_no_BOM.json":            {false, false},
+	"i_structure_UTF-8_BOM_empty_object.json": {false, false},
+
+	// there is no nesting limit.
+	"i_structure_500_nested_arrays.json": {true, true},
+}
+
+func TestJSONTestSuite(t *testing.T) {
+	files, err := filepath.Glob("testdata/JSONTestSuite/test_parsing/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("expecting the JSONTestSuite files to exist: %v", err)
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want struct{ decode, strict bool }
+			switch name[:2] {
+			case "y_":
+				want.decode, want.strict = true, true
+			case "n_":
+				want.decode, want.strict = false, false
+			case "i_":
+				var ok bool
+				if want, ok = implementationDefined[name]; !ok {
+					t.Fatal("expecting the behavior to be documented in implementationDefined")
+				}
+			}
+			if _, err := Decode(data); (err == nil) != want.decode {
+				t.Errorf("Decode: accepted = %v, want %v (err: %v)", err == nil, want.decode, err)
+			}
+			d := NewDecoder(data)
+			d.Strict()
+			if _, err := d.Decode(); (err == nil) != want.strict {
+				t.Errorf("Strict: accepted = %v, want %v (err: %v)", err == nil, want.strict, err)
+			}
+			// extensions that were enabled before Strict are disabled.
+			d = NewDecoder(data)
+			d.JSON5()
+			d.Strict()
+			if _, err := d.Decode(); (err == nil) != want.strict {
+				t.Errorf("JSON5 and Strict: accepted = %v, want %v (err: %v)", err == nil, want.strict, err)
+			}
+			if strings.HasPrefix(name, "n_") {
+				// the lenient mode must not panic or hang on invalid input.
+				if _, errs := DecodeLenient(data); len(errs) == 0 {
+					t.Error("DecodeLenient: expecting errors")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package djson
+
+// maxLinearKeys is the number of members up to which the keys of a Lazy
+// object are searched linearly. Bigger objects are indexed with a map.
+const maxLinearKeys = 8
+
+// Lazy is a JSON value that is decoded on demand. Objects and arrays are
+// kept as ranges of the input, and they are scanned only when their members
+// or elements are accessed. The children are cached, so each part of the
+// input is scanned at most once, and the parts that are not accessed are
+// never decoded.
+//
+//	doc, err := djson.DecodeLazy(data)
+//	if err != nil {
+//		return err
+//	}
+//	name := doc.Get("person").Get("name").Value()
+//
+// The methods of Lazy can be called on a nil *Lazy, and they return the zero
+// value, so the accessors can be chained without checking each step. Note
+// that a Lazy references the input, so the input must not be modified while
+// the Lazy is used, and it is not safe for concurrent use.
+type Lazy struct {
+	data    []byte
+	typ     ValueType
+	scanned bool
+	keys    []string
+	index   map[string]int
+	elems   []Lazy
+	decoded bool
+	val     interface{}
+}
+
+// DecodeLazy validates the JSON-encoded data, and returns it as a Lazy
+// value. The validation does not allocate, and it reports the same errors as
+// Decode.
+func DecodeLazy(data []byte) (*Lazy, error) {
+	d := getDecoder(data)
+	defer putDecoder(d)
+	d.skipSpaces()
+	start := d.pos
+	if err := d.skip(); err != nil {
+		return nil, err
+	}
+	end := d.pos
+	if c := d.skipSpaces(); d.pos < d.end {
+		return nil, d.error(c, "after top-level value")
+	}
+	l := new(Lazy)
+	l.init(data[start:end])
+	return l, nil
+}
+
+// init sets the raw value of l, and its type.
+func (l *Lazy) init(data []byte) {
+	l.data = data
+	l.typ = rawType(data[0])
+}
+
+// rawType returns the type of a valid JSON value by its first character.
+func rawType(c byte) ValueType {
+	switch c {
+	case '{':
+		return Object
+	case '[':
+		return Array
+	case '"':
+		return String
+	case 't', 'f':
+		return Bool
+	case 'n':
+		return Null
+	default:
+		return Number
+	}
+}
+
+// Type returns the JSON type of the value, or Unknown if l is nil.
+func (l *Lazy) Type() ValueType {
+	if l == nil {
+		return Unknown
+	}
+	return l.typ
+}
+
+// Raw returns the JSON encoding of the value, as it appears in the input.
+func (l *Lazy) Raw() []byte {
+	if l == nil {
+		return nil
+	}
+	return l.data
+}
+
+// Len returns the number of members of an object, or the number of elements
+// of an array. It returns 0 for other values.
+func (l *Lazy) Len() int {
+	if l == nil {
+		return 0
+	}
+	l.scan()
+	return len(l.elems)
+}
+
+// Keys returns the keys of an object, in their order in the input. If a key
+// appears more than once, it is returned at the position of its first
+// occurrence. The returned slice must not be modified.
+func (l *Lazy) Keys() []string {
+	if l == nil || l.typ != Object {
+		return nil
+	}
+	l.scan()
+	return l.keys
+}
+
+// Get returns the member of an object with the given key, or nil if l is not
+// an object or the key does not exist. Like in Decode, the last occurrence of
+// a duplicate key wins.
+func (l *Lazy) Get(key string) *Lazy {
+	if l == nil || l.typ != Object {
+		return nil
+	}
+	l.scan()
+	if i, ok := l.find(key); ok {
+		return &l.elems[i]
+	}
+	return nil
+}
+
+// Index returns the i'th element of an array, or nil if l is not an array or
+// i is out of range.
+func (l *Lazy) Index(i int) *Lazy {
+	if l == nil || l.typ != Array {
+		return nil
+	}
+	l.scan()
+	if i < 0 || i >= len(l.elems) {
+		return nil
+	}
+	return &l.elems[i]
+}
+
+// Value decodes the value, and returns it like Decode does. The result is
+// cached, so the same map or slice is returned on subsequent calls. If the
+// object or the array was already scanned, it is built from the values of its
+// cached children, and they share memory with the result.
+func (l *Lazy) Value() interface{} {
+	if l == nil {
+		return nil
+	}
+	if l.decoded {
+		return l.val
+	}
+	switch {
+	case l.scanned && l.typ == Object:
+		m := make(map[string]interface{}, len(l.keys))
+		for i, k := range l.keys {
+			m[k] = l.elems[i].Value()
+		}
+		l.val = m
+	case l.scanned && l.typ == Array:
+		a := make([]interface{}, len(l.elems))
+		for i := range l.elems {
+			a[i] = l.elems[i].Value()
+		}
+		l.val = a
+	default:
+		d := getDecoder(l.data)
+		// the data was already validated.
+		l.val, _ = d.any()
+		putDecoder(d)
+	}
+	l.decoded = true
+	return l.val
+}
+
+// scan splits an object or an array into its children, once.
+func (l *Lazy) scan() {
+	if l.scanned || (l.typ != Object && l.typ != Array) {
+		return
+	}
+	l.scanned = true
+	d := getDecoder(l.data)
+	defer putDecoder(d)
+	// the data was already validated, so the tokens are not checked.
+	d.pos++
+	if c := d.skipSpaces(); c == '}' || c == ']' {
+		return
+	}
+	for {
+		var (
+			k   string
+			dup = -1
+		)
+		if l.typ == Object {
+			d.skipSpaces()
+			k, _ = d.key()
+			if i, ok := l.find(k); ok {
+				dup = i
+			}
+			d.skipSpaces()
+			d.pos++
+		}
+		d.skipSpaces()
+		start := d.pos
+		d.skip()
+		if dup >= 0 {
+			l.elems[dup] = Lazy{}
+			l.elems[dup].init(l.data[start:d.pos])
+		} else {
+			l.elems = append(l.elems, Lazy{})
+			l.elems[len(l.elems)-1].init(l.data[start:d.pos])
+			if l.typ == Object {
+				l.addKey(k)
+			}
+		}
+		if d.skipSpaces() != ',' {
+			return
+		}
+		d.pos++
+	}
+}
+
+// find returns the position of the given key in an object that was scanned.
+func (l *Lazy) find(key string) (int, bool) {
+	if l.index != nil {
+		i, ok := l.index[key]
+		return i, ok
+	}
+	for i, k := range l.keys {
+		if k == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// addKey adds a new key to an object, and indexes its keys with a map once
+// there are too many of them for a linear search.
+func (l *Lazy) addKey(key string) {
+	l.keys = append(l.keys, key)
+	switch n := len(l.keys); {
+	case l.index != nil:
+		l.index[key] = n - 1
+	case n > maxLinearKeys:
+		l.index = make(map[string]int, 2*n)
+		for i, k := range l.keys {
+			l.index[k] = i
+		}
+	}
+}
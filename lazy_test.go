@@ -0,0 +1,142 @@
+package djson
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// materialize builds the value of l from its children.
+func materialize(l *Lazy) interface{} {
+	switch l.Type() {
+	case Object:
+		obj := make(map[string]interface{}, l.Len())
+		for _, k := range l.Keys() {
+			obj[k] = materialize(l.Get(k))
+		}
+		return obj
+	case Array:
+		array := make([]interface{}, l.Len())
+		for i := range array {
+			array[i] = materialize(l.Index(i))
+		}
+		return array
+	}
+	return l.Value()
+}
+
+func TestDecodeLazy(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, `"k%d": %d, "k%d": [%d], `, i, i, i%5, i)
+	}
+	b.WriteString(`"k0": "last"}`)
+
+	inputs := []string{string(allValueIndent), b.String(), ` [] `, `{}`, `"aé"`, `-1.5`, `null`, `[{"a": [{}]}, true]`}
+	for _, tt := range decodeTests {
+		inputs = append(inputs, tt.in)
+	}
+	files, _ := filepath.Glob("testdata/JSONTestSuite/test_parsing/*.json")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, string(data))
+	}
+	for _, in := range inputs {
+		expected, err := Decode([]byte(in))
+		l, err1 := DecodeLazy([]byte(in))
+		if !reflect.DeepEqual(err, err1) {
+			t.Errorf("DecodeLazy(%.40q): expecting the same error as Decode\n\tactual: %v\n\twant: %v", in, err1, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if typ := Type(expected); l.Type() != typ {
+			t.Errorf("DecodeLazy(%.40q): type = %v; want %v", in, l.Type(), typ)
+		}
+		if val := materialize(l); !reflect.DeepEqual(val, expected) {
+			t.Errorf("DecodeLazy(%.40q) = %v; want %v", in, val, expected)
+		}
+		if val := l.Value(); !reflect.DeepEqual(val, expected) {
+			t.Errorf("DecodeLazy(%.40q).Value() = %v; want %v", in, val, expected)
+		}
+	}
+
+	// keys are unique, in the order of their first occurrence.
+	l, _ := DecodeLazy([]byte(b.String()))
+	if keys := l.Keys(); len(keys) != 20 || keys[0] != "k0" || keys[1] != "k1" || keys[5] != "k5" || l.Len() != 20 {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+	if v := l.Get("k0").Value(); v != "last" {
+		t.Errorf(`Get("k0") = %v; want "last"`, v)
+	}
+	if raw := string(l.Get("k3").Raw()); raw != "[18]" {
+		t.Errorf(`Get("k3").Raw() = %s; want [18]`, raw)
+	}
+
+	// the accessors can be chained.
+	l, _ = DecodeLazy(allValueIndent)
+	if v := l.Get("object_6").Get("c").Get("cc").Get("cca").Index(6).Value(); v != true {
+		t.Errorf("unexpected value: %v", v)
+	}
+	for _, l := range []*Lazy{l.Get("missing").Get("a"), l.Get("array_2").Index(4), l.Get("array_2").Get("a"), l.Get("int_1").Index(0)} {
+		if l != nil || l.Value() != nil || l.Len() != 0 || l.Keys() != nil || l.Raw() != nil || l.Type() != Unknown {
+			t.Errorf("expecting a nil value, got: %v", l)
+		}
+	}
+
+	// children are cached.
+	if l.Get("object_6") != l.Get("object_6") || l.Value().(map[string]interface{})["int_1"] != 42.0 {
+		t.Error("expecting the children to be cached")
+	}
+
+	// the value of a scanned object is built from its cached children.
+	l, _ = DecodeLazy(allValueIndent)
+	c := l.Get("object_6").Get("c").Value()
+	val := l.Value()
+	if expected, _ := Decode(allValueIndent); !reflect.DeepEqual(val, expected) {
+		t.Errorf("Value() = %v; want %v", val, expected)
+	}
+	object := val.(map[string]interface{})["object_6"].(map[string]interface{})
+	if reflect.ValueOf(object["c"]).Pointer() != reflect.ValueOf(c).Pointer() {
+		t.Error("expecting the value to reuse the decoded children")
+	}
+}
+
+func TestDecodeLazyAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the decoders pool drops items under the race detector")
+	}
+	var b strings.Builder
+	b.WriteString(`{"id": 1`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, `, "field_%d": {"name": "value %d", "tags": ["a", "b", %d], "ok": true}`, i, i, i)
+	}
+	b.WriteString("}")
+	data := []byte(b.String())
+
+	lazy := testing.AllocsPerRun(100, func() {
+		l, err := DecodeLazy(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Get("field_7").Get("name").Value() != "value 7" {
+			t.Fatal("unexpected value")
+		}
+	})
+	full := testing.AllocsPerRun(100, func() {
+		Decode(data)
+	})
+	// the members that are not accessed are not decoded. Only the keys of
+	// the scanned object(51 of them) and the accessed member are allocated.
+	if lazy > full/5 {
+		t.Errorf("DecodeLazy allocates %v times; want at most a fifth of Decode(%v)", lazy, full)
+	}
+}
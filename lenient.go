@@ -0,0 +1,85 @@
+package djson
+
+// DecodeLenient is like Decode, but it does not stop at the first syntax
+// error. Instead, it records the error, skips the broken value (or object
+// member) until the next ',', ']' or '}' and continues from there.
+// It returns the best-effort partial tree together with all the errors that
+// were encountered, in the order they were found.
+//
+// Note that the returned tree may be nil if the top-level value itself is
+// broken (e.g. a scalar), and that arrays and objects never contain the
+// values that failed to decode.
+//
+//	val, errs := djson.NewDecoder([]byte(`[1, x, 3}`)).DecodeLenient()
+//	// val  = []interface{}{1.0, 3.0}
+//	// errs = [invalid character 'x' looking for beginning of value
+//	//         invalid character '}' after array element]
+func (d *Decoder) DecodeLenient() (interface{}, []*SyntaxError) {
+	d.lenient = true
+	d.errs = nil
+	val, err := d.any()
+	if err != nil {
+		d.report(err)
+	} else if c := d.skipSpaces(); d.pos < d.end {
+		d.report(d.error(c, "after top-level value"))
+	}
+	return val, d.errs
+}
+
+// report records an error that occurred in lenient mode. Predefined errors
+// and errors that are not syntax errors(e.g. numbers out of range) are
+// recorded with the current offset.
+func (d *Decoder) report(err error) {
+	se, ok := err.(*SyntaxError)
+	if !ok || se.Offset < 0 {
+		off := d.pos
+		if off < d.end {
+			off++
+		}
+		se = &SyntaxError{err.Error(), off}
+	}
+	// several levels of nesting may report the same unexpected EOF.
+	if n := len(d.errs); n > 0 && d.errs[n-1].Offset == se.Offset && d.errs[n-1].msg == se.msg {
+		return
+	}
+	d.errs = append(d.errs, se)
+}
+
+// resync rewinds the decoder to the start of the broken value and skips ahead
+// to the next ',', ']' or '}' that is not nested in that value. It returns the
+// character it stopped at without consuming it, or 0 if the end of the input
+// was reached.
+func (d *Decoder) resync(start int) byte {
+	depth := 0
+	for d.pos = start; d.pos < d.end; d.pos++ {
+		switch c := d.data[d.pos]; c {
+		case '/':
+			// step back, as the comment leaves the position after its end.
+			if d.comments && d.comment() {
+				d.pos--
+			}
+		case '"', '\'':
+			if c == '\'' && !d.json5 {
+				break
+			}
+			for d.pos++; d.pos < d.end && d.data[d.pos] != c; d.pos++ {
+				if d.data[d.pos] == '\\' {
+					d.pos++
+				}
+			}
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth == 0 {
+				return c
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return c
+			}
+		}
+	}
+	d.pos = d.end
+	return 0
+}
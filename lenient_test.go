@@ -0,0 +1,98 @@
+package djson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeLenient(t *testing.T) {
+	for i, tt := range []struct {
+		in       string
+		errs     []*SyntaxError
+		expected interface{}
+	}{
+		// valid input
+		{in: `{"a":[1,2]}`, expected: map[string]interface{}{"a": []interface{}{1.0, 2.0}}},
+		{in: `"a"`, expected: "a"},
+
+		// broken array elements
+		{
+			in:       `[1, x, 3]`,
+			expected: []interface{}{1.0, 3.0},
+			errs:     []*SyntaxError{{"invalid character 'x' looking for beginning of value", 5}},
+		},
+		{
+			in:       `[1, 2 3, 4]`,
+			expected: []interface{}{1.0, 2.0, 4.0},
+			errs:     []*SyntaxError{{"invalid character '3' after array element", 7}},
+		},
+		{
+			in:       `[1,,2,]`,
+			expected: []interface{}{1.0, 2.0},
+			errs: []*SyntaxError{
+				{"invalid character ',' looking for beginning of value", 4},
+				{"invalid character ']' looking for beginning of value", 7},
+			},
+		},
+		{
+			in:       `[1, x, 3}`,
+			expected: []interface{}{1.0, 3.0},
+			errs: []*SyntaxError{
+				{"invalid character 'x' looking for beginning of value", 5},
+				{"invalid character '}' after array element", 9},
+			},
+		},
+		{
+			in:       `["a\x", "b"]`,
+			expected: []interface{}{"b"},
+			errs:     []*SyntaxError{{"invalid character 'x' in string escape code", 5}},
+		},
+
+		// broken object members
+		{
+			in:       `{"a" 1, "b": 2}`,
+			expected: map[string]interface{}{"b": 2.0},
+			errs:     []*SyntaxError{{"invalid character '1' after object key", 6}},
+		},
+		{
+			in:       `{a: 1, "b": {"c": tru, "d": [1, .]}, "e": 3}`,
+			expected: map[string]interface{}{"b": map[string]interface{}{"d": []interface{}{1.0}}, "e": 3.0},
+			errs: []*SyntaxError{
+				{"invalid character 'a' looking for beginning of object key string", 2},
+				{"invalid character 'r' in literal true", 20},
+				{"invalid character '.' looking for beginning of value", 33},
+			},
+		},
+		{
+			in:       `{"X":12x}`,
+			expected: map[string]interface{}{"X": 12.0},
+			errs:     []*SyntaxError{{"invalid character 'x' after object key:value pair", 8}},
+		},
+
+		// unexpected EOF is reported once
+		{
+			in:       `[1, {"a": [2`,
+			expected: []interface{}{1.0, map[string]interface{}{"a": []interface{}{2.0}}},
+			errs:     []*SyntaxError{{"unexpected end of JSON input", 12}},
+		},
+
+		// top-level errors
+		{
+			in:   `x`,
+			errs: []*SyntaxError{{"invalid character 'x' looking for beginning of value", 1}},
+		},
+		{
+			in:       `[1] 2`,
+			expected: []interface{}{1.0},
+			errs:     []*SyntaxError{{"invalid character '2' after top-level value", 5}},
+		},
+	} {
+		out, errs := DecodeLenient([]byte(tt.in))
+		if !reflect.DeepEqual(errs, tt.errs) {
+			t.Errorf("#%d: %v, want %v", i, errs, tt.errs)
+		}
+		if !reflect.DeepEqual(out, tt.expected) {
+			t.Errorf("#%d: %v, want %v", i, out, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package djson
+
+import "reflect"
+
+// MergePatch applies a JSON Merge Patch(RFC 7386) on target, which is a
+// decoded value(e.g. the result of Decode), and returns the merged value.
+//
+// If the patch is an object, each of its members is merged into target: a
+// null member deletes the key from target, an object member is merged into
+// the value of the key recursively, and any other member replaces it. If
+// target is not an object, it is replaced with an empty object before the
+// merge. If the patch is not an object, it replaces target as a whole.
+//
+//	doc = djson.MergePatch(doc, map[string]interface{}{"title": "Hello!", "author": nil})
+//
+// Unlike Set, target is not modified, and the result does not share memory
+// with target or with the patch.
+func MergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return clone(patch)
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	} else {
+		t = clone(t).(map[string]interface{})
+	}
+	merge(t, p)
+	return t
+}
+
+// merge merges the patch into t in place. Values of t that are merged are
+// expected to be owned by t.
+func merge(t, patch map[string]interface{}) {
+	for k, v := range patch {
+		switch pv := v.(type) {
+		case nil:
+			delete(t, k)
+		case map[string]interface{}:
+			tv, ok := t[k].(map[string]interface{})
+			if !ok {
+				tv = make(map[string]interface{}, len(pv))
+			}
+			merge(tv, pv)
+			t[k] = tv
+		default:
+			t[k] = clone(v)
+		}
+	}
+}
+
+// MergePatchBytes is like MergePatch, but it works on the JSON encoding of
+// the target and the patch. Both are decoded with Decode, and the result is
+// encoded with Encode, so its keys are sorted. A *SyntaxError is returned if
+// one of them is invalid.
+func MergePatchBytes(target, patch []byte) ([]byte, error) {
+	t, err := Decode(target)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Decode(patch)
+	if err != nil {
+		return nil, err
+	}
+	// the decoded target is not used elsewhere, so it is merged in place.
+	if pm, ok := p.(map[string]interface{}); ok {
+		tm, ok := t.(map[string]interface{})
+		if !ok {
+			tm = make(map[string]interface{}, len(pm))
+		}
+		merge(tm, pm)
+		return Encode(tm)
+	}
+	return Encode(p)
+}
+
+// CreateMergePatch returns a JSON Merge Patch that transforms a into b, such
+// that MergePatch(a, CreateMergePatch(a, b)) is equal to b. Both values are
+// decoded values, and they are not modified.
+//
+// Keys of a that are missing in b are set to null in the patch, and objects
+// that exist in both are diffed recursively. Arrays and other values are
+// replaced as a whole if they differ. If a and b are equal objects, the
+// patch is an empty object. Note that a merge patch can not set a member to
+// null, so null members of b are deleted in the patch if they exist in a, and
+// omitted otherwise.
+func CreateMergePatch(a, b interface{}) interface{} {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		return clone(b)
+	}
+	return diff(am, bm)
+}
+
+// diff returns the merge patch between two objects.
+func diff(a, b map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, bv := range b {
+		av, ok := a[k]
+		if ok && reflect.DeepEqual(av, bv) {
+			continue
+		}
+		am, aok := av.(map[string]interface{})
+		bm, bok := bv.(map[string]interface{})
+		switch {
+		case aok && bok:
+			patch[k] = diff(am, bm)
+		case bv == nil:
+			// null can not be set with a merge patch. It is written
+			// only when it deletes an existing key.
+			if ok {
+				patch[k] = nil
+			}
+		default:
+			patch[k] = clone(bv)
+		}
+	}
+	return patch
+}
@@ -0,0 +1,111 @@
+package djson
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mergeTests are the examples from the appendix of RFC 7386.
+var mergeTests = []struct {
+	target, patch, out string
+}{
+	{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+	{`{"a":"b"}`, `{"a":null}`, `{}`},
+	{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+	{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+	{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+	{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+	{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+	{`{"a":"b"}`, `["c"]`, `["c"]`},
+	{`{"a":"foo"}`, `null`, `null`},
+	{`{"a":"foo"}`, `"bar"`, `"bar"`},
+	{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+	{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+	{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+}
+
+func TestMergePatch(t *testing.T) {
+	for _, tt := range mergeTests {
+		target, _ := Decode([]byte(tt.target))
+		patch, _ := Decode([]byte(tt.patch))
+		expected, _ := Decode([]byte(tt.out))
+		if out := MergePatch(target, patch); !reflect.DeepEqual(out, expected) {
+			t.Errorf("MergePatch(%s, %s) = %v; want %s", tt.target, tt.patch, out, tt.out)
+		}
+		// the target is not modified.
+		if orig, _ := Decode([]byte(tt.target)); !reflect.DeepEqual(target, orig) {
+			t.Errorf("MergePatch(%s, %s): expecting the target not to be modified, got: %v", tt.target, tt.patch, target)
+		}
+		out, err := MergePatchBytes([]byte(tt.target), []byte(tt.patch))
+		if expected, _ := Encode(expected); err != nil || string(out) != string(expected) {
+			t.Errorf("MergePatchBytes(%s, %s) = %s, %v; want %s", tt.target, tt.patch, out, err, expected)
+		}
+	}
+}
+
+func TestMergePatchBytesError(t *testing.T) {
+	for _, tt := range []struct {
+		target, patch string
+	}{
+		{`{"a":}`, `{}`},
+		{`{}`, `{"a"`},
+		{``, `{}`},
+	} {
+		if _, err := MergePatchBytes([]byte(tt.target), []byte(tt.patch)); err == nil {
+			t.Errorf("MergePatchBytes(%s, %s): expecting an error", tt.target, tt.patch)
+		}
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	for _, tt := range []struct {
+		a, b, patch string
+	}{
+		{`{"a":"b"}`, `{"a":"b"}`, `{}`},
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b","b":"c"}`, `{"b":"c"}`, `{"a":null}`},
+		{`{"a":{"b":"c","d":1}}`, `{"a":{"b":"e","d":1}}`, `{"a":{"b":"e"}}`},
+		{`{"a":{"b":"c"}}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"a":[1,2]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"a":1}`, `{"a":{"b":{"c":2}}}`, `{"a":{"b":{"c":2}}}`},
+		{`{"a":1}`, `{"a":null}`, `{"a":null}`},
+		{`{}`, `{"a":null}`, `{}`},
+		{`[1]`, `{"a":1}`, `{"a":1}`},
+		{`{"a":1}`, `[1]`, `[1]`},
+		{`{"a":1}`, `null`, `null`},
+		{`1`, `2`, `2`},
+	} {
+		a, _ := Decode([]byte(tt.a))
+		b, _ := Decode([]byte(tt.b))
+		expected, _ := Decode([]byte(tt.patch))
+		patch := CreateMergePatch(a, b)
+		if !reflect.DeepEqual(patch, expected) {
+			t.Errorf("CreateMergePatch(%s, %s) = %v; want %s", tt.a, tt.b, patch, tt.patch)
+		}
+	}
+	// the patch transforms a into b.
+	for _, tt := range mergeTests {
+		a, _ := Decode([]byte(tt.target))
+		b, _ := Decode([]byte(tt.out))
+		if out := MergePatch(a, CreateMergePatch(a, b)); !reflect.DeepEqual(out, b) && !hasNull(b) {
+			t.Errorf("MergePatch(%s, CreateMergePatch(%s, %s)) = %v; want %s", tt.target, tt.target, tt.out, out, tt.out)
+		}
+	}
+}
+
+// hasNull reports whether an object has a null member, which can not be
+// created with a merge patch.
+func hasNull(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, v := range m {
+		if v == nil || hasNull(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package djson
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps the first size bytes of f into memory for reading. It reports
+// false if the file can not be mapped, and it should be read instead.
+func mmap(f *os.File, size int) ([]byte, bool) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, false
+	}
+	// the data is decoded from start to end, so let the kernel read ahead.
+	syscall.Madvise(data, syscall.MADV_SEQUENTIAL)
+	return data, true
+}
+
+// munmap unmaps data that was mapped by mmap.
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package djson
+
+import "os"
+
+// mmap is not supported on this platform, and files are always read.
+func mmap(f *os.File, size int) ([]byte, bool) {
+	return nil, false
+}
+
+// munmap is never called on this platform.
+func munmap(data []byte) error {
+	return nil
+}
@@ -0,0 +1,166 @@
+package djson
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// An ElementError describes an error in an element of an array that was
+// decoded by DecodeArrayParallel.
+type ElementError struct {
+	Index  int   // index of the element in the array
+	Offset int   // error occurred after reading Offset bytes of the array
+	Err    error // the error of the element
+}
+
+func (e *ElementError) Error() string {
+	return "element " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the element.
+func (e *ElementError) Unwrap() error { return e.Err }
+
+// DecodeArrayParallel is the same as DecodeArray, but it decodes the elements
+// of the array concurrently, using the given number of workers(or GOMAXPROCS
+// if it is not positive). It is useful for big arrays, such as batch files
+// that contain a single array of events.
+//
+// The data is scanned once in order to find the boundaries of the elements,
+// and then the elements are decoded in chunks, each worker with its own
+// Decoder. The result holds the elements in their original order.
+// If an element is invalid, the returned error is an *ElementError that
+// holds the index of the first invalid element and the offset of the error.
+// Syntax errors that are not part of the elements(e.g. a missing comma or an
+// empty element) are reported like in DecodeArray.
+func DecodeArrayParallel(data []byte, workers int) ([]interface{}, error) {
+	seps, ok := splitArray(data)
+	if !ok {
+		// let the sequential decoder report the error.
+		return DecodeArray(data)
+	}
+	n := len(seps) - 1
+	if n == 1 && blank(data, seps[0], seps[1]) {
+		return make([]interface{}, 0), nil
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		first  *ElementError
+		failed int32
+		next   int64
+		array  = make([]interface{}, n)
+		// a few chunks per worker, in order to balance the work between
+		// them when the elements are not of the same size.
+		chunk = (n + workers*4 - 1) / (workers * 4)
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			d := getDecoder(nil)
+			defer putDecoder(d)
+			// the chunks are taken in order, so once an element fails, all
+			// the chunks before it are already taken.
+			for atomic.LoadInt32(&failed) == 0 {
+				start := int(atomic.AddInt64(&next, int64(chunk))) - chunk
+				if start >= n {
+					return
+				}
+				end := start + chunk
+				if end > n {
+					end = n
+				}
+				for i := start; i < end; i++ {
+					off := seps[i] + 1
+					d.Reset(data[off:seps[i+1]])
+					v, err := d.Decode()
+					if err != nil {
+						if se, ok := err.(*SyntaxError); ok && se.Offset >= 0 {
+							off += se.Offset
+						}
+						mu.Lock()
+						if first == nil || i < first.Index {
+							first = &ElementError{Index: i, Offset: off, Err: err}
+						}
+						mu.Unlock()
+						atomic.StoreInt32(&failed, 1)
+						return
+					}
+					array[i] = v
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if first != nil {
+		return nil, first
+	}
+	return array, nil
+}
+
+// splitArray finds the boundaries of the elements of the top-level array in
+// data. It returns the positions of the separators of the elements; that is,
+// the opening bracket, the commas between the elements and the closing
+// bracket. It reports false if the structure of the array is invalid.
+func splitArray(data []byte) ([]int, bool) {
+	i := whitespace(data)
+	if i == len(data) || data[i] != '[' {
+		return nil, false
+	}
+	var (
+		depth int
+		seps  = []int{i}
+	)
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			for i++; i < len(data); i++ {
+				i += plainString(data[i:])
+				if i == len(data) || data[i] == '"' {
+					break
+				}
+				if data[i] == '\\' {
+					i++
+				}
+			}
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth > 0 {
+				depth--
+				break
+			}
+			// an empty element(e.g. "[1,]") is invalid, unless the array
+			// itself is empty.
+			if data[i] == '}' || len(seps) > 1 && blank(data, seps[len(seps)-1], i) {
+				return nil, false
+			}
+			seps = append(seps, i)
+			// only white spaces are allowed after the array.
+			return seps, i+1+whitespace(data[i+1:]) == len(data)
+		case ',':
+			if depth == 0 {
+				if blank(data, seps[len(seps)-1], i) {
+					return nil, false
+				}
+				seps = append(seps, i)
+			}
+		}
+	}
+	return nil, false
+}
+
+// blank reports whether there are only white spaces between the separators
+// at the given positions.
+func blank(data []byte, sep, next int) bool {
+	return whitespace(data[sep+1:next]) == next-sep-1
+}
@@ -0,0 +1,69 @@
+package djson
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeArrayParallel(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < 500; i++ {
+		if i > 0 {
+			b.WriteString(",\n")
+		}
+		b.Write(allValueIndent)
+		b.WriteString(`, "a,]\"}", [1, {"b": "]"}], 1.5, null`)
+	}
+	b.WriteString("]")
+	big := b.String()
+
+	for _, in := range []string{big, `[]`, ` [ ] `, `[1]`, `[[], {}, "x"]`, ` [1, 2, 3] `} {
+		expected, err := DecodeArray([]byte(in))
+		if err != nil {
+			t.Fatalf("expecting DecodeArray(%.20s) not to fail: %v", in, err)
+		}
+		for _, workers := range []int{0, 1, 3, 16} {
+			out, err := DecodeArrayParallel([]byte(in), workers)
+			if err != nil {
+				t.Errorf("DecodeArrayParallel(%.20s, %d): unexpected error: %v", in, workers, err)
+			}
+			if !reflect.DeepEqual(out, expected) {
+				t.Errorf("DecodeArrayParallel(%.20s, %d): expecting the same value as DecodeArray", in, workers)
+			}
+		}
+	}
+
+	// errors in the elements are reported with their index and offset.
+	for _, tt := range []struct {
+		in     string
+		index  int
+		offset int
+		err    string
+	}{
+		{`[1, 2, trux, 4]`, 2, 9, "invalid character 'r' in literal true"},
+		{`[1, [2, x], 3, y]`, 1, 9, "invalid character 'x' looking for beginning of value"},
+		{`[1, {"a" 1}]`, 1, 10, "invalid character '1' after object key"},
+		{big[:len(big)-1] + `, x]`, 2500, len(big) + 2, "invalid character 'x' looking for beginning of value"},
+	} {
+		_, err := DecodeArrayParallel([]byte(tt.in), 4)
+		var ee *ElementError
+		if !errors.As(err, &ee) {
+			t.Errorf("DecodeArrayParallel(%.20s): expecting an ElementError, got: %v", tt.in, err)
+			continue
+		}
+		if ee.Index != tt.index || ee.Offset != tt.offset || ee.Err.Error() != tt.err {
+			t.Errorf("DecodeArrayParallel(%.20s) = %d, %d, %q; want %d, %d, %q", tt.in, ee.Index, ee.Offset, ee.Err, tt.index, tt.offset, tt.err)
+		}
+	}
+
+	// errors in the structure of the array are reported like in DecodeArray.
+	for _, in := range []string{``, `{}`, `[1, 2`, `[1, 2] x`, `[1, "2]`, `[1}`, `[1,, 2]`, `[1,]`, `[,1]`, `[ , ]`, `[1, [2], ]`} {
+		_, expected := DecodeArray([]byte(in))
+		if _, err := DecodeArrayParallel([]byte(in), 4); !reflect.DeepEqual(err, expected) {
+			t.Errorf("DecodeArrayParallel(%s) = %v; want %v", in, err, expected)
+		}
+	}
+}
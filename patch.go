@@ -0,0 +1,329 @@
+package djson
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A PatchError describes an operation of a JSON Patch that failed.
+type PatchError struct {
+	msg   string // description of error
+	Index int    // index of the operation in the patch
+	Op    string // name of the operation, if it is known
+}
+
+func (e *PatchError) Error() string {
+	if e.Op == "" {
+		return "operation " + strconv.Itoa(e.Index) + ": " + e.msg
+	}
+	return "operation " + strconv.Itoa(e.Index) + " (" + e.Op + "): " + e.msg
+}
+
+// ApplyPatch applies a JSON Patch(RFC 6902) on doc, which is a decoded value
+// (e.g. the result of Decode), and returns the patched document. The patch is
+// a decoded JSON Patch document; That is, an array of operation objects:
+//
+//	[
+//		{"op": "add", "path": "/tags/-", "value": "new"},
+//		{"op": "remove", "path": "/image"},
+//		{"op": "replace", "path": "/name", "value": "Ariel"},
+//		{"op": "move", "from": "/ip", "path": "/geo/ip"},
+//		{"op": "copy", "from": "/date", "path": "/created"},
+//		{"op": "test", "path": "/score", "value": 99}
+//	]
+//
+// The paths are JSON Pointers(RFC 6901). The patch is applied atomically; If
+// one of the operations fails, ApplyPatch returns a *PatchError that names the
+// index of the operation, and doc is left as is. The patch is applied on a
+// deep copy of doc, so doc is never modified, and the result does not share
+// memory with doc or with the patch.
+func ApplyPatch(doc interface{}, patch []interface{}) (interface{}, error) {
+	doc = clone(doc)
+	for i, op := range patch {
+		var err *PatchError
+		if doc, err = applyOp(doc, op); err != nil {
+			err.Index = i
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// applyOp applies a single operation of a patch on doc.
+func applyOp(doc interface{}, op interface{}) (interface{}, *PatchError) {
+	o, ok := op.(map[string]interface{})
+	if !ok {
+		return nil, &PatchError{msg: "expecting an operation object, found " + Type(op).String()}
+	}
+	name, ok := o["op"].(string)
+	if !ok {
+		return nil, &PatchError{msg: `missing "op" member`}
+	}
+	fail := func(msg string) (interface{}, *PatchError) {
+		return nil, &PatchError{msg: msg, Op: name}
+	}
+	path, err := patchPointer(o, "path")
+	if err != "" {
+		return fail(err)
+	}
+	var (
+		value    interface{}
+		hasValue bool
+		from     []string
+	)
+	switch name {
+	case "add", "replace", "test":
+		if value, hasValue = o["value"]; !hasValue {
+			return fail(`missing "value" member`)
+		}
+	case "move", "copy":
+		if from, err = patchPointer(o, "from"); err != "" {
+			return fail(err)
+		}
+	case "remove":
+	default:
+		return fail("unknown operation " + strconv.Quote(name))
+	}
+
+	switch name {
+	case "add":
+		doc, err = patchAdd(doc, path, clone(value))
+	case "remove":
+		doc, _, err = patchRemove(doc, path)
+	case "replace":
+		if _, err = patchGet(doc, path); err == "" {
+			if len(path) == 0 {
+				doc = clone(value)
+			} else {
+				doc, _, _ = patchRemove(doc, path)
+				doc, err = patchAdd(doc, path, clone(value))
+			}
+		}
+	case "move":
+		if len(from) < len(path) && isPrefix(from, path) {
+			return fail("can not move " + formatPointer(from) + " into one of its children")
+		}
+		if len(from) == 0 {
+			// the root is moved into itself.
+			break
+		}
+		var v interface{}
+		if doc, v, err = patchRemove(doc, from); err == "" {
+			doc, err = patchAdd(doc, path, v)
+		}
+	case "copy":
+		var v interface{}
+		if v, err = patchGet(doc, from); err == "" {
+			doc, err = patchAdd(doc, path, clone(v))
+		}
+	case "test":
+		var v interface{}
+		if v, err = patchGet(doc, path); err == "" && !reflect.DeepEqual(v, value) {
+			err = "test failed: the value of " + formatPointer(path) + " is not equal to the given value"
+		}
+	}
+	if err != "" {
+		return fail(err)
+	}
+	return doc, nil
+}
+
+// patchPointer returns the parsed JSON Pointer in the given member of the
+// operation.
+func patchPointer(op map[string]interface{}, member string) ([]string, string) {
+	s, ok := op[member].(string)
+	if !ok {
+		return nil, "missing " + strconv.Quote(member) + " member"
+	}
+	p, ok := parsePointer(s)
+	if !ok {
+		return nil, "invalid JSON pointer " + strconv.Quote(s)
+	}
+	return p, ""
+}
+
+// parsePointer parses a JSON Pointer into its reference tokens, and reports
+// whether it is valid.
+func parsePointer(s string) ([]string, bool) {
+	if s == "" {
+		return nil, true
+	}
+	if s[0] != '/' {
+		return nil, false
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		if !strings.Contains(t, "~") {
+			continue
+		}
+		// ~1 is unescaped before ~0, so "~01" is "~1" and not "/".
+		for j := 0; j < len(t); j++ {
+			if t[j] == '~' && (j+1 == len(t) || t[j+1] != '0' && t[j+1] != '1') {
+				return nil, false
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, true
+}
+
+// formatPointer returns the JSON Pointer of the given tokens.
+func formatPointer(tokens []string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+	return strconv.Quote(b.String())
+}
+
+// isPrefix reports whether prefix is a prefix of path.
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pointerIndex parses a reference token of an array, and reports whether it
+// is a valid index, which is less than n, or equal to it if end is true.
+func pointerIndex(t string, n int, end bool) (int, bool) {
+	if t == "" || len(t) > 1 && t[0] == '0' {
+		return 0, false
+	}
+	for i := 0; i < len(t); i++ {
+		if t[i] < '0' || t[i] > '9' {
+			return 0, false
+		}
+	}
+	i, err := strconv.Atoi(t)
+	if err != nil || i > n || i == n && !end {
+		return 0, false
+	}
+	return i, true
+}
+
+// patchGet returns the value that the pointer references in doc.
+func patchGet(doc interface{}, path []string) (interface{}, string) {
+	for i, t := range path {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if doc, ok = v[t]; !ok {
+				return nil, "path " + formatPointer(path[:i+1]) + " does not exist"
+			}
+		case []interface{}:
+			n, ok := pointerIndex(t, len(v), false)
+			if !ok {
+				return nil, "path " + formatPointer(path[:i+1]) + " does not exist"
+			}
+			doc = v[n]
+		default:
+			return nil, "path " + formatPointer(path[:i+1]) + " does not exist"
+		}
+	}
+	return doc, ""
+}
+
+// patchUpdate calls fn with the container of the last token of the path, and
+// replaces the container with its result. It returns the new doc.
+func patchUpdate(doc interface{}, path []string, fn func(c interface{}, t string) (interface{}, string)) (interface{}, string) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := patchGet(doc, path[:1])
+	if err != "" {
+		return nil, err
+	}
+	if child, err = patchUpdate(child, path[1:], fn); err != "" {
+		return nil, err
+	}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		v[path[0]] = child
+	case []interface{}:
+		n, _ := pointerIndex(path[0], len(v), false)
+		v[n] = child
+	}
+	return doc, ""
+}
+
+// patchAdd adds the value in the given path of doc, and returns the new doc.
+func patchAdd(doc interface{}, path []string, value interface{}) (interface{}, string) {
+	if len(path) == 0 {
+		return value, ""
+	}
+	return patchUpdate(doc, path, func(c interface{}, t string) (interface{}, string) {
+		switch v := c.(type) {
+		case map[string]interface{}:
+			v[t] = value
+			return v, ""
+		case []interface{}:
+			if t == "-" {
+				return append(v, value), ""
+			}
+			n, ok := pointerIndex(t, len(v), true)
+			if !ok {
+				return nil, "path " + formatPointer(path) + ": invalid index for an array of length " + strconv.Itoa(len(v))
+			}
+			v = append(v, nil)
+			copy(v[n+1:], v[n:])
+			v[n] = value
+			return v, ""
+		}
+		return nil, "path " + formatPointer(path) + ": can not add a member to a " + Type(c).String() + " value"
+	})
+}
+
+// patchRemove removes the value in the given path of doc, and returns the new
+// doc and the removed value.
+func patchRemove(doc interface{}, path []string) (interface{}, interface{}, string) {
+	if len(path) == 0 {
+		return nil, nil, "can not remove the root of the document"
+	}
+	if _, err := patchGet(doc, path); err != "" {
+		return nil, nil, err
+	}
+	var removed interface{}
+	doc, err := patchUpdate(doc, path, func(c interface{}, t string) (interface{}, string) {
+		switch v := c.(type) {
+		case map[string]interface{}:
+			removed = v[t]
+			delete(v, t)
+			return v, ""
+		case []interface{}:
+			n, _ := pointerIndex(t, len(v), false)
+			removed = v[n]
+			copy(v[n:], v[n+1:])
+			v[len(v)-1] = nil
+			return v[:len(v)-1], ""
+		}
+		return c, ""
+	})
+	return doc, removed, err
+}
+
+// clone returns a deep copy of a decoded value.
+func clone(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = clone(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			a[i] = clone(e)
+		}
+		return a
+	}
+	return v
+}
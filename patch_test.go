@@ -0,0 +1,116 @@
+package djson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	for _, tt := range []struct {
+		doc   string
+		patch string
+		out   string
+		err   string
+	}{
+		// add
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux"}]`, `{"baz": "qux", "foo": "bar"}`, ""},
+		{`{"foo": ["bar", "baz"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo": ["bar", "qux", "baz"]}`, ""},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo": ["bar", "qux"]}`, ""},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/-", "value": ["abc"]}]`, `{"foo": ["bar", ["abc"]]}`, ""},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/foo", "value": null}]`, `{"foo": null}`, ""},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "", "value": [1]}]`, `[1]`, ""},
+		{`{"a": {"b": [{"c": 1}]}}`, `[{"op": "add", "path": "/a/b/0/d", "value": 2}]`, `{"a": {"b": [{"c": 1, "d": 2}]}}`, ""},
+		{`{}`, `[{"op": "add", "path": "/a~1b/c~0d", "value": 1}]`, ``, `operation 0 (add): path "/a~1b" does not exist`},
+		{`{"a/b": {}}`, `[{"op": "add", "path": "/a~1b/c~0d", "value": 1}]`, `{"a/b": {"c~d": 1}}`, ""},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/2", "value": 1}]`, ``, `operation 0 (add): path "/foo/2": invalid index for an array of length 1`},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/01", "value": 1}]`, ``, `operation 0 (add): path "/foo/01": invalid index for an array of length 1`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/foo/a", "value": 1}]`, ``, `operation 0 (add): path "/foo/a": can not add a member to a string value`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz/bat", "value": 1}]`, ``, `operation 0 (add): path "/baz" does not exist`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz"}]`, ``, `operation 0 (add): missing "value" member`},
+		// remove
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`, `{"foo": "bar"}`, ""},
+		{`{"foo": ["bar", "qux", "baz"]}`, `[{"op": "remove", "path": "/foo/1"}]`, `{"foo": ["bar", "baz"]}`, ""},
+		{`{"foo": ["bar"]}`, `[{"op": "remove", "path": "/foo/1"}]`, ``, `operation 0 (remove): path "/foo/1" does not exist`},
+		{`{"foo": ["bar"]}`, `[{"op": "remove", "path": "/foo/-"}]`, ``, `operation 0 (remove): path "/foo/-" does not exist`},
+		{`{"foo": "bar"}`, `[{"op": "remove", "path": ""}]`, ``, `operation 0 (remove): can not remove the root of the document`},
+		// replace
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": "boo"}]`, `{"baz": "boo", "foo": "bar"}`, ""},
+		{`{"foo": [1, 2, 3]}`, `[{"op": "replace", "path": "/foo/1", "value": {"a": 1}}]`, `{"foo": [1, {"a": 1}, 3]}`, ""},
+		{`{"foo": 1}`, `[{"op": "replace", "path": "", "value": "x"}]`, `"x"`, ""},
+		{`{"foo": 1}`, `[{"op": "replace", "path": "/bar", "value": "x"}]`, ``, `operation 0 (replace): path "/bar" does not exist`},
+		// move
+		{`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`, `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`, ""},
+		{`{"foo": ["all", "grass", "cows", "eat"]}`, `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, `{"foo": ["all", "cows", "eat", "grass"]}`, ""},
+		{`{"foo": 1}`, `[{"op": "move", "from": "/foo", "path": "/foo"}]`, `{"foo": 1}`, ""},
+		{`{"foo": {"a": 1}}`, `[{"op": "move", "from": "/foo", "path": "/foo/b"}]`, ``, `operation 0 (move): can not move "/foo" into one of its children`},
+		{`{"foo": 1}`, `[{"op": "move", "from": "/bar", "path": "/baz"}]`, ``, `operation 0 (move): path "/bar" does not exist`},
+		{`{"foo": 1}`, `[{"op": "move", "path": "/baz"}]`, ``, `operation 0 (move): missing "from" member`},
+		// copy
+		{`{"foo": {"a": [1]}}`, `[{"op": "copy", "from": "/foo", "path": "/bar"}, {"op": "add", "path": "/bar/a/-", "value": 2}]`, `{"foo": {"a": [1]}, "bar": {"a": [1, 2]}}`, ""},
+		{`{"foo": [1, 2]}`, `[{"op": "copy", "from": "/foo/1", "path": "/foo/0"}]`, `{"foo": [2, 1, 2]}`, ""},
+		{`{"foo": 1}`, `[{"op": "copy", "from": "/bar", "path": "/baz"}]`, ``, `operation 0 (copy): path "/bar" does not exist`},
+		// test
+		{`{"baz": "qux", "foo": ["a", 2, "c"]}`, `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`, `{"baz": "qux", "foo": ["a", 2, "c"]}`, ""},
+		{`{"foo": {"a": [1, {"b": null}]}}`, `[{"op": "test", "path": "/foo", "value": {"a": [1, {"b": null}]}}]`, `{"foo": {"a": [1, {"b": null}]}}`, ""},
+		{`{"baz": "qux"}`, `[{"op": "test", "path": "/baz", "value": "bar"}]`, ``, `operation 0 (test): test failed: the value of "/baz" is not equal to the given value`},
+		{`{"foo": [1, 2]}`, `[{"op": "test", "path": "/foo", "value": [2, 1]}]`, ``, `operation 0 (test): test failed: the value of "/foo" is not equal to the given value`},
+		{`{"/": 1, "~": 2}`, `[{"op": "test", "path": "/~1", "value": 1}, {"op": "test", "path": "/~0", "value": 2}]`, `{"/": 1, "~": 2}`, ""},
+		// the failing operation is named, and the previous ones are not applied.
+		{`{"a": 1}`, `[{"op": "add", "path": "/b", "value": 2}, {"op": "remove", "path": "/a"}, {"op": "test", "path": "/b", "value": 3}]`, ``, `operation 2 (test): test failed: the value of "/b" is not equal to the given value`},
+		{`{"a": 1}`, `[{"op": "remove", "path": "/a"}, {"op": "copy", "from": "/a", "path": "/b"}]`, ``, `operation 1 (copy): path "/a" does not exist`},
+		// invalid operations
+		{`{}`, `[1]`, ``, `operation 0: expecting an operation object, found number`},
+		{`{}`, `[{"path": "/a"}]`, ``, `operation 0: missing "op" member`},
+		{`{}`, `[{"op": "test", "path": "/a", "value": 1}, {"op": "get", "path": "/a"}]`, ``, `operation 0 (test): path "/a" does not exist`},
+		{`{"a": 1}`, `[{"op": "test", "path": "/a", "value": 1}, {"op": "get", "path": "/a"}]`, ``, `operation 1 (get): unknown operation "get"`},
+		{`{}`, `[{"op": "remove"}]`, ``, `operation 0 (remove): missing "path" member`},
+		{`{}`, `[{"op": "remove", "path": "a"}]`, ``, `operation 0 (remove): invalid JSON pointer "a"`},
+		{`{}`, `[{"op": "remove", "path": "/a~2"}]`, ``, `operation 0 (remove): invalid JSON pointer "/a~2"`},
+		{`{}`, `[{"op": "remove", "path": "/a~"}]`, ``, `operation 0 (remove): invalid JSON pointer "/a~"`},
+	} {
+		doc, err := Decode([]byte(tt.doc))
+		if err != nil {
+			t.Fatalf("decoding %s: %v", tt.doc, err)
+		}
+		patch, err := DecodeArray([]byte(tt.patch))
+		if err != nil {
+			t.Fatalf("decoding %s: %v", tt.patch, err)
+		}
+		out, err := ApplyPatch(doc, patch)
+		// the document is never modified.
+		if expected, _ := Decode([]byte(tt.doc)); !reflect.DeepEqual(doc, expected) {
+			t.Errorf("ApplyPatch(%s, %s): expecting the document not to be modified, got: %v", tt.doc, tt.patch, doc)
+		}
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ApplyPatch(%s, %s): error = %v; want %q", tt.doc, tt.patch, err, tt.err)
+			}
+			if _, ok := err.(*PatchError); !ok {
+				t.Errorf("ApplyPatch(%s, %s): expecting a PatchError, got: %#v", tt.doc, tt.patch, err)
+			}
+			continue
+		}
+		expected, _ := Decode([]byte(tt.out))
+		if err != nil || !reflect.DeepEqual(out, expected) {
+			t.Errorf("ApplyPatch(%s, %s) = %v, %v; want %s", tt.doc, tt.patch, out, err, tt.out)
+		}
+	}
+}
+
+func TestApplyPatchNoSharing(t *testing.T) {
+	doc := map[string]interface{}{"a": []interface{}{1.0}}
+	value := map[string]interface{}{"c": 1.0}
+	patch := []interface{}{
+		map[string]interface{}{"op": "add", "path": "/b", "value": value},
+	}
+	out, err := ApplyPatch(doc, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := out.(map[string]interface{})
+	m["a"].([]interface{})[0] = 2.0
+	m["b"].(map[string]interface{})["c"] = 2.0
+	if doc["a"].([]interface{})[0] != 1.0 || value["c"] != 1.0 {
+		t.Errorf("expecting the result not to share memory with its inputs, got: %v, %v", doc, value)
+	}
+}
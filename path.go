@@ -0,0 +1,147 @@
+package djson
+
+import "fmt"
+
+// A PathError is returned by Set when the path does not match the structure
+// of the value.
+type PathError struct {
+	msg  string   // description of error
+	Path []string // the path up to the element that failed
+}
+
+func (e *PathError) Error() string { return e.msg }
+
+// notIndexError is returned when the last element of path goes through an
+// array, and it is not an array index.
+func notIndexError(path []string) *PathError {
+	k := path[len(path)-1]
+	return &PathError{fmt.Sprintf("can not set %q in an array: %q is not an array index", path, k), path}
+}
+
+// indexRangeError is returned when the last element of path is an index that
+// is beyond the end of an array of length n.
+func indexRangeError(path []string, n int) *PathError {
+	return &PathError{fmt.Sprintf("can not set %q: index out of range for an array of length %d", path, n), path}
+}
+
+// notContainerError is returned when the last element of path goes through a
+// value of type t, that is not an object or an array.
+func notContainerError(path []string, t ValueType) *PathError {
+	return &PathError{fmt.Sprintf("can not set %q in a %s value", path, t), path}
+}
+
+// Set sets the value in the given path of root, which is a decoded value
+// (e.g. the result of Decode). A path is a list of object keys and array
+// indexes in brackets(e.g. []string{"users", "[0]", "name"}), like in
+// EachPath.
+//
+// Missing objects and arrays in the path are created, according to the type
+// of the next element in the path, and so are the null values in the path.
+// Arrays can be extended only by appending; That is, an index that is equal
+// to the length of the array adds a new element, like "-" in JSON Patch. Set
+// returns a *PathError if the path goes through a value that is not an object
+// or an array, if an element of the path that goes through an array is not an
+// array index, or if the index is beyond the end of the array. In this case,
+// root is not modified.
+//
+// The tree is modified in place, but the returned root must be used instead
+// of root, as it is a new value if root was replaced(i.e. the path is empty
+// or root is nil), or if root is an array that was extended.
+//
+//	event, _ = djson.Set(event, []string{"geo", "country"}, "IL")
+func Set(root interface{}, path []string, value interface{}) (interface{}, error) {
+	return set(root, path, 0, value)
+}
+
+// set sets the value in path[i:] of v, and returns the new v.
+func set(v interface{}, path []string, i int, value interface{}) (interface{}, error) {
+	if i == len(path) {
+		return value, nil
+	}
+	k := path[i]
+	switch c := v.(type) {
+	case map[string]interface{}:
+		elem, err := set(c[k], path, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		c[k] = elem
+		return c, nil
+	case []interface{}:
+		n, ok := arrayIndex(k)
+		if !ok {
+			return nil, notIndexError(path[:i+1])
+		}
+		if n > len(c) {
+			return nil, indexRangeError(path[:i+1], len(c))
+		}
+		elem := interface{}(nil)
+		if n < len(c) {
+			elem = c[n]
+		}
+		elem, err := set(elem, path, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		if n == len(c) {
+			return append(c, elem), nil
+		}
+		c[n] = elem
+		return c, nil
+	case nil:
+		if _, ok := arrayIndex(k); ok {
+			return set(make([]interface{}, 0), path, i, value)
+		}
+		return set(make(map[string]interface{}), path, i, value)
+	default:
+		return nil, notContainerError(path[:i+1], Type(v))
+	}
+}
+
+// Delete deletes the value in the given path of root, and reports whether it
+// existed. Array elements that follow a deleted element are shifted. Like
+// Set, the tree is modified in place, and the returned root must be used
+// instead of root. An empty path deletes root itself, and returns nil.
+func Delete(root interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, true
+	}
+	k := path[0]
+	switch c := root.(type) {
+	case map[string]interface{}:
+		elem, ok := c[k]
+		if !ok {
+			return root, false
+		}
+		if len(path) == 1 {
+			delete(c, k)
+			return c, true
+		}
+		if elem, ok = Delete(elem, path[1:]); ok {
+			c[k] = elem
+		}
+		return c, ok
+	case []interface{}:
+		n, ok := arrayIndex(k)
+		if !ok || n >= len(c) {
+			return root, false
+		}
+		if len(path) == 1 {
+			copy(c[n:], c[n+1:])
+			// release the last element, so it can be collected.
+			c[len(c)-1] = nil
+			return c[:len(c)-1], true
+		}
+		elem, ok := Delete(c[n], path[1:])
+		if ok {
+			c[n] = elem
+		}
+		return c, ok
+	}
+	return root, false
+}
+
+// Exists reports whether the given path exists in root.
+func Exists(root interface{}, path []string) bool {
+	return ValueOf(root).Get(path...).Exists()
+}
@@ -0,0 +1,115 @@
+package djson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	for _, tt := range []struct {
+		in    string
+		path  []string
+		value interface{}
+		out   string
+		err   string
+	}{
+		{`{"a": 1}`, []string{"a"}, "x", `{"a": "x"}`, ""},
+		{`{"a": 1}`, []string{"b", "c"}, 2.0, `{"a": 1, "b": {"c": 2}}`, ""},
+		{`{"a": null}`, []string{"a", "[0]", "c"}, true, `{"a": [{"c": true}]}`, ""},
+		{`{"a": [1, 2]}`, []string{"a", "[0]"}, nil, `{"a": [null, 2]}`, ""},
+		{`{"a": [1, 2]}`, []string{"a", "[2]"}, 3.0, `{"a": [1, 2, 3]}`, ""},
+		{`[]`, []string{"[0]", "a"}, "b", `[{"a": "b"}]`, ""},
+		{`{"a": {"[0]": 1}}`, []string{"a", "[0]"}, 2.0, `{"a": {"[0]": 2}}`, ""},
+		{`{"a": 1}`, nil, "x", `"x"`, ""},
+		{`null`, []string{"a"}, 1.0, `{"a": 1}`, ""},
+		{`{"a": "b"}`, []string{"a", "c"}, 1.0, "", `can not set ["a" "c"] in a string value`},
+		{`{"a": {"b": [1]}}`, []string{"a", "b", "c", "d"}, 1.0, "", `can not set ["a" "b" "c"] in an array: "c" is not an array index`},
+		{`{"a": {"b": [1]}}`, []string{"a", "b", "[-1]"}, 1.0, "", `can not set ["a" "b" "[-1]"] in an array: "[-1]" is not an array index`},
+		{`{"a": {"b": [1]}}`, []string{"a", "b", "[0]", "c"}, 1.0, "", `can not set ["a" "b" "[0]" "c"] in a number value`},
+		{`true`, []string{"[0]"}, 1.0, "", `can not set ["[0]"] in a boolean value`},
+		{`{"a": [1, 2]}`, []string{"a", "[3]"}, 4.0, "", `can not set ["a" "[3]"]: index out of range for an array of length 2`},
+		{`{"a": [1, 2]}`, []string{"a", "[1000000000]"}, 4.0, "", `can not set ["a" "[1000000000]"]: index out of range for an array of length 2`},
+		{`{"a": null}`, []string{"a", "[1]", "c"}, true, "", `can not set ["a" "[1]"]: index out of range for an array of length 0`},
+	} {
+		root, _ := Decode([]byte(tt.in))
+		val, err := Set(root, tt.path, tt.value)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Set(%s, %q): error = %v; want %q", tt.in, tt.path, err, tt.err)
+			}
+			if pe, ok := err.(*PathError); !ok || len(pe.Path) == 0 {
+				t.Errorf("Set(%s, %q): expecting a PathError, got: %#v", tt.in, tt.path, err)
+			}
+			// the root is not modified on errors.
+			if expected, _ := Decode([]byte(tt.in)); !reflect.DeepEqual(root, expected) {
+				t.Errorf("Set(%s, %q): expecting the root not to be modified, got: %v", tt.in, tt.path, root)
+			}
+			continue
+		}
+		expected, _ := Decode([]byte(tt.out))
+		if err != nil || !reflect.DeepEqual(val, expected) {
+			t.Errorf("Set(%s, %q) = %v, %v; want %s", tt.in, tt.path, val, err, tt.out)
+		}
+		if !Exists(val, tt.path) {
+			t.Errorf("Set(%s, %q): expecting the path to exist", tt.in, tt.path)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		path []string
+		out  string
+		ok   bool
+	}{
+		{`{"a": 1, "b": 2}`, []string{"a"}, `{"b": 2}`, true},
+		{`{"a": {"b": [1, 2, 3]}}`, []string{"a", "b", "[1]"}, `{"a": {"b": [1, 3]}}`, true},
+		{`{"a": {"b": [1, 2, 3]}}`, []string{"a", "b", "[2]"}, `{"a": {"b": [1, 2]}}`, true},
+		{`[[1, 2], 3]`, []string{"[0]", "[0]"}, `[[2], 3]`, true},
+		{`[{"a": 1}]`, []string{"[0]", "a"}, `[{}]`, true},
+		{`{"a": null}`, []string{"a"}, `{}`, true},
+		{`{"a": 1}`, nil, `null`, true},
+		{`{"a": 1}`, []string{"b"}, `{"a": 1}`, false},
+		{`{"a": 1}`, []string{"a", "b"}, `{"a": 1}`, false},
+		{`{"a": [1]}`, []string{"a", "[1]"}, `{"a": [1]}`, false},
+		{`{"a": [1]}`, []string{"a", "0"}, `{"a": [1]}`, false},
+		{`{"a": {"b": 1}}`, []string{"a", "c", "d"}, `{"a": {"b": 1}}`, false},
+		{`"a"`, []string{"a"}, `"a"`, false},
+	} {
+		root, _ := Decode([]byte(tt.in))
+		val, ok := Delete(root, tt.path)
+		expected, _ := Decode([]byte(tt.out))
+		if ok != tt.ok || !reflect.DeepEqual(val, expected) {
+			t.Errorf("Delete(%s, %q) = %v, %v; want %s, %v", tt.in, tt.path, val, ok, tt.out, tt.ok)
+		}
+	}
+
+	// the deleted elements are released.
+	array := []interface{}{1.0, "a", 3.0}
+	Delete(array, []string{"[1]"})
+	if array[2] != nil {
+		t.Errorf("expecting the last element to be released, got: %v", array[2])
+	}
+}
+
+func TestExists(t *testing.T) {
+	root, _ := Decode(allValueIndent)
+	for _, tt := range []struct {
+		path []string
+		ok   bool
+	}{
+		{nil, true},
+		{[]string{"null_1"}, true},
+		{[]string{"array_4", "[2]", "[0]", "[0]", "[0]"}, true},
+		{[]string{"object_6", "c", "cc", "ccd", "ccda"}, true},
+		{[]string{"object_6", "c", "cc", "ccd", "ccdb"}, false},
+		{[]string{"array_4", "[3]"}, false},
+		{[]string{"string_1", "a"}, false},
+		{[]string{"null_1", "a"}, false},
+	} {
+		if ok := Exists(root, tt.path); ok != tt.ok {
+			t.Errorf("Exists(%q) = %v; want %v", tt.path, ok, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package djson
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+)
+
+const (
+	// batchLines and batchSize are the bounds of the batches of lines that
+	// are handed to the workers of a Pipeline, in order to not pay the cost
+	// of the synchronization for every line.
+	batchLines = 64
+	batchSize  = 64 << 10
+)
+
+// A Result holds the outcome of a line in a Pipeline.
+type Result struct {
+	Line  int         // number of the line in the stream, starting from 1
+	Value interface{} // the decoded value, after the transform
+	Err   error       // the error of the decoding or the transform
+}
+
+// A Pipeline decodes the lines of an NDJSON stream concurrently. The lines
+// are read from an io.Reader, decoded by a pool of workers, each with its own
+// Decoder, passed to an optional transform function, and delivered in their
+// original order.
+//
+// Errors of a single line(e.g. invalid JSON) do not stop the pipeline; They
+// are delivered in the Err field of its Result. Empty lines are skipped.
+//
+//	p := djson.NewPipeline(os.Stdin, 4)
+//	p.Transform(func(v interface{}) (interface{}, error) {
+//		return v.(map[string]interface{})["id"], nil
+//	})
+//	err := p.Run(ctx, func(r djson.Result) error {
+//		if r.Err != nil {
+//			return fmt.Errorf("line %d: %w", r.Line, r.Err)
+//		}
+//		fmt.Println(r.Value)
+//		return nil
+//	})
+type Pipeline struct {
+	r         io.Reader
+	workers   int
+	buffer    int
+	setup     func(*Decoder)
+	transform func(interface{}) (interface{}, error)
+	err       error
+}
+
+// NewPipeline returns a new Pipeline that reads from r, and decodes its lines
+// with the given number of workers(or GOMAXPROCS if it is not positive).
+func NewPipeline(r io.Reader, workers int) *Pipeline {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Pipeline{r: r, workers: workers, buffer: 2 * workers}
+}
+
+// Decoder sets a function that configures the Decoder of each worker before
+// it starts(e.g. to call InternKeys or Strict on it). The function is called
+// from the goroutine of Run, once for each worker.
+func (p *Pipeline) Decoder(setup func(d *Decoder)) {
+	p.setup = setup
+}
+
+// Transform sets a function that is applied by the workers on each decoded
+// value. Its result is delivered instead of the value, and its error is
+// delivered in the Err field of the Result.
+func (p *Pipeline) Transform(fn func(v interface{}) (interface{}, error)) {
+	p.transform = fn
+}
+
+// Buffer sets the number of batches of lines(up to 64 lines each) that are
+// read ahead of the consumer. When the consumer falls behind, the reading
+// is paused until it catches up. The default is twice the number of workers.
+func (p *Pipeline) Buffer(n int) {
+	if n > 0 {
+		p.buffer = n
+	}
+}
+
+// batch is a chunk of consecutive lines, that is decoded by a single worker.
+type batch struct {
+	line int      // number of the first line
+	data []byte   // the lines
+	ends []int    // end offsets of the lines in data
+	out  []Result // results of the non-empty lines
+	done chan struct{}
+}
+
+// Run runs the pipeline, and calls fn with the results of the lines, in their
+// order in the stream. It stops when the stream is exhausted, the context is
+// canceled or fn returns an error, and returns the reason for stopping(nil
+// for the end of the stream, or the error of the reader).
+//
+// If Run stops before the end of the stream, the reading goroutine exits when
+// its pending Read call returns.
+func (p *Pipeline) Run(ctx context.Context, fn func(r Result) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		work  = make(chan *batch, p.workers)
+		queue = make(chan *batch, p.buffer)
+		rerr  = make(chan error, 1)
+	)
+	for i := 0; i < p.workers; i++ {
+		d := NewDecoder(nil)
+		if p.setup != nil {
+			p.setup(d)
+		}
+		go p.work(d, work)
+	}
+	go func() {
+		rerr <- p.read(ctx, work, queue)
+		close(work)
+		close(queue)
+	}()
+	// the batches are queued in the order they were read, so the results
+	// are delivered in order, even if the workers finish out of order.
+	for b := range queue {
+		select {
+		case <-b.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		for _, r := range b.out {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+	}
+	return <-rerr
+}
+
+// Results runs the pipeline in the background, and returns a channel that
+// delivers the results of the lines in their order in the stream. The channel
+// is closed when the pipeline stops, and Err reports the reason for stopping.
+// The consumer must drain the channel or cancel the context.
+func (p *Pipeline) Results(ctx context.Context) <-chan Result {
+	ch := make(chan Result)
+	go func() {
+		defer close(ch)
+		p.err = p.Run(ctx, func(r Result) error {
+			select {
+			case ch <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return ch
+}
+
+// Err returns the error that stopped the pipeline that was started with
+// Results. It must be called after the channel was closed, and it returns nil
+// if the pipeline stopped at the end of the stream.
+func (p *Pipeline) Err() error {
+	return p.err
+}
+
+// read splits the stream into batches of lines, and sends each of them to
+// the workers and to the queue of the consumer. The queue is bounded, so the
+// reading blocks when the consumer falls behind.
+func (p *Pipeline) read(ctx context.Context, work, queue chan<- *batch) error {
+	var (
+		br   = bufio.NewReaderSize(p.r, batchSize)
+		line = 1
+		err  error
+	)
+	for err == nil {
+		b := &batch{line: line, done: make(chan struct{})}
+		for len(b.ends) < batchLines && len(b.data) < batchSize && err == nil {
+			var s []byte
+			start := len(b.data)
+			s, err = br.ReadSlice('\n')
+			// lines that are longer than the buffer are read in parts.
+			for err == bufio.ErrBufferFull {
+				b.data = append(b.data, s...)
+				s, err = br.ReadSlice('\n')
+			}
+			if b.data = append(b.data, s...); len(b.data) > start {
+				b.ends = append(b.ends, len(b.data))
+			}
+		}
+		if len(b.ends) == 0 {
+			break
+		}
+		line += len(b.ends)
+		select {
+		case queue <- b:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case work <- b:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// work decodes the batches of lines it receives with the given Decoder, until
+// the channel is closed.
+func (p *Pipeline) work(d *Decoder, work <-chan *batch) {
+	for b := range work {
+		start := 0
+		for i, end := range b.ends {
+			data := b.data[start:end]
+			start = end
+			if whitespace(data) == len(data) {
+				continue
+			}
+			d.Reset(data)
+			v, err := d.Decode()
+			if err == nil && p.transform != nil {
+				v, err = p.transform(v)
+			}
+			b.out = append(b.out, Result{Line: b.line + i, Value: v, Err: err})
+		}
+		close(b.done)
+	}
+}
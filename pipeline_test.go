@@ -0,0 +1,136 @@
+package djson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestPipeline(t *testing.T) {
+	var (
+		b        strings.Builder
+		expected []Result
+		long     = strings.Repeat("x", 3*batchSize)
+	)
+	for i := 1; i <= 1000; i++ {
+		switch {
+		case i == 550:
+			fmt.Fprintf(&b, "{\"id\": %d, \"long\": %q}\n", i, long)
+			expected = append(expected, Result{Line: i, Value: float64(i)})
+		case i%100 == 0:
+			b.WriteString("\n")
+		case i%101 == 0:
+			b.WriteString("  \r\n")
+		case i%77 == 0:
+			fmt.Fprintf(&b, "{\"id\": %d,}\n", i)
+			_, err := Decode([]byte(fmt.Sprintf("{\"id\": %d,}", i)))
+			expected = append(expected, Result{Line: i, Err: err})
+		default:
+			fmt.Fprintf(&b, "{\"id\": %d, \"tags\": [\"a\", \"b\"]}\r\n", i)
+			expected = append(expected, Result{Line: i, Value: float64(i)})
+		}
+	}
+	// the last line has no newline.
+	b.WriteString(`{"id": 1001}`)
+	expected = append(expected, Result{Line: 1001, Value: float64(1001)})
+	in := b.String()
+
+	for _, workers := range []int{0, 1, 3, 8} {
+		p := NewPipeline(iotest.HalfReader(strings.NewReader(in)), workers)
+		p.Buffer(2)
+		var decoders int
+		p.Decoder(func(d *Decoder) {
+			d.InternKeys(10)
+			decoders++
+		})
+		p.Transform(func(v interface{}) (interface{}, error) {
+			return v.(map[string]interface{})["id"], nil
+		})
+		var results []Result
+		err := p.Run(context.Background(), func(r Result) error {
+			results = append(results, r)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Run: unexpected error: %v", err)
+		}
+		if want := p.workers; decoders != want {
+			t.Errorf("expecting the Decoder function to be called %d times, got: %d", want, decoders)
+		}
+		if !reflect.DeepEqual(results, expected) {
+			t.Errorf("Run(workers=%d): unexpected results", workers)
+		}
+
+		p = NewPipeline(strings.NewReader(in), workers)
+		p.Transform(func(v interface{}) (interface{}, error) {
+			return v.(map[string]interface{})["id"], nil
+		})
+		results = results[:0]
+		for r := range p.Results(context.Background()) {
+			results = append(results, r)
+		}
+		if err := p.Err(); err != nil {
+			t.Fatalf("Results: unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(results, expected) {
+			t.Errorf("Results(workers=%d): unexpected results", workers)
+		}
+	}
+
+	// errors of the transform are delivered with the line.
+	p := NewPipeline(strings.NewReader("1\n2\n3\n"), 2)
+	errOdd := errors.New("odd")
+	p.Transform(func(v interface{}) (interface{}, error) {
+		if v.(float64) == 3 {
+			return nil, errOdd
+		}
+		return v, nil
+	})
+	var results []Result
+	p.Run(context.Background(), func(r Result) error {
+		results = append(results, r)
+		return nil
+	})
+	if want := []Result{{1, 1.0, nil}, {2, 2.0, nil}, {3, nil, errOdd}}; !reflect.DeepEqual(results, want) {
+		t.Errorf("Run = %v; want %v", results, want)
+	}
+
+	// the callback stops the pipeline.
+	errStop := errors.New("stop")
+	var n int
+	err := NewPipeline(strings.NewReader(in), 4).Run(context.Background(), func(r Result) error {
+		if n++; r.Line == 299 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || n != 295 {
+		t.Errorf("Run = %v after %d results; want %v after 295", err, n, errStop)
+	}
+
+	// the errors of the reader are returned.
+	errRead := errors.New("read")
+	err = NewPipeline(io.MultiReader(strings.NewReader("1\n2\n"), iotest.ErrReader(errRead)), 2).Run(context.Background(), func(Result) error { return nil })
+	if err != errRead {
+		t.Errorf("Run = %v; want %v", err, errRead)
+	}
+
+	// the context cancels the pipeline.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p = NewPipeline(strings.NewReader(in), 4)
+	n = 0
+	for range p.Results(ctx) {
+		if n++; n == 10 {
+			cancel()
+		}
+	}
+	if err := p.Err(); err != context.Canceled {
+		t.Errorf("Err = %v; want %v", err, context.Canceled)
+	}
+}
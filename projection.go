@@ -0,0 +1,179 @@
+package djson
+
+import "strings"
+
+// projection is the set of the object members that are decoded when Only is
+// set. Each key holds the projection of its value, or nil if the value is
+// decoded as a whole.
+type projection map[string]projection
+
+// Only makes the Decoder decode only the object members in the given paths,
+// and skip the rest of them without allocating. The result is a sparse tree
+// that holds the selected members only. A path is a list of keys separated by
+// dots(e.g. "Image.Src"), and arrays are transparent to it; That is, the
+// path "tags.name" selects the "name" member of every object in the "tags"
+// array. Members that are not in the data are omitted from the result.
+//
+//	dec := djson.NewDecoder(data)
+//	dec.Only("APP_ID", "IP", "Date")
+//	event, err := dec.DecodeObject()
+//
+// The skipped members are still validated, so the Decoder returns the same
+// errors as without the projection. Calling Only without arguments disables
+// the projection. The projection is kept when the Decoder is reused with
+// Reset, and it disables the StructuralIndex.
+func (d *Decoder) Only(paths ...string) {
+	d.proj = nil
+	if len(paths) == 0 {
+		return
+	}
+	d.proj = make(projection)
+	for _, path := range paths {
+		p := d.proj
+		keys := strings.Split(path, ".")
+		for i, k := range keys {
+			sub, ok := p[k]
+			if ok && sub == nil {
+				// the member is already decoded as a whole.
+				break
+			}
+			if i == len(keys)-1 {
+				p[k] = nil
+				break
+			}
+			if !ok {
+				sub = make(projection)
+				p[k] = sub
+			}
+			p = sub
+		}
+	}
+}
+
+// projectedKey called by `object` after reading `"` when a projection is set.
+// It is the same as `key`, but it reports whether the member is part of the
+// projection, and it does not allocate keys that are not.
+func (d *Decoder) projectedKey() (string, bool, error) {
+	start := d.pos + 1
+	unquote, err := d.scanString()
+	if err != nil {
+		return "", false, err
+	}
+	data := d.data[start : d.pos-1]
+	if unquote {
+		var (
+			ok       bool
+			stackbuf [64]byte
+		)
+		if data, ok = d.unquote(data, stackbuf[:]); !ok {
+			return "", false, ErrStringEscape
+		}
+	}
+	// the compiler does not allocate for the string conversion in lookups.
+	if _, ok := d.proj[string(data)]; !ok {
+		return "", false, nil
+	}
+	switch {
+	case d.keys != nil:
+		return d.intern(data), true, nil
+	case !unquote:
+		return d.slice(start, d.pos-1), true, nil
+	default:
+		return string(data), true, nil
+	}
+}
+
+// skip validates the value at the current position and moves past it,
+// without decoding it. Values in the JSON5 and the lenient modes are decoded
+// and dropped, in order to report the same errors as `any`.
+func (d *Decoder) skip() error {
+	if d.json5 || d.lenient {
+		_, err := d.any()
+		return err
+	}
+	switch c := d.skipSpaces(); {
+	case c == '"':
+		_, err := d.scanString()
+		return err
+	case '0' <= c && c <= '9':
+		_, err := d.number()
+		return err
+	case c == '-' && d.end-d.pos > 1 && '0' <= d.data[d.pos+1] && d.data[d.pos+1] <= '9':
+		d.pos++
+		_, err := d.number()
+		return err
+	case c == '[':
+		return d.skipArray()
+	case c == '{':
+		return d.skipObject()
+	default:
+		// literals are not allocated.
+		_, err := d.any()
+		return err
+	}
+}
+
+// skipArray is the `skip` version of `array`.
+func (d *Decoder) skipArray() error {
+	d.pos++
+	if d.skipSpaces() == ']' {
+		d.pos++
+		return nil
+	}
+	for {
+		if err := d.skip(); err != nil {
+			return err
+		}
+		switch c := d.skipSpaces(); c {
+		case ',':
+			d.pos++
+			if d.trailing && d.skipSpaces() == ']' {
+				d.pos++
+				return nil
+			}
+		case ']':
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "after array element")
+		}
+	}
+}
+
+// skipObject is the `skip` version of `object`.
+func (d *Decoder) skipObject() error {
+	d.pos++
+	if d.skipSpaces() == '}' {
+		d.pos++
+		return nil
+	}
+	for {
+		switch c := d.skipSpaces(); {
+		case c == '"':
+			if _, err := d.scanString(); err != nil {
+				return err
+			}
+		case c == '}' && d.trailing:
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "looking for beginning of object key string")
+		}
+		if c := d.skipSpaces(); c != ':' {
+			return d.error(c, "after object key")
+		}
+		d.pos++
+		if err := d.skip(); err != nil {
+			return err
+		}
+		switch c := d.skipSpaces(); c {
+		case ',':
+			d.pos++
+		case '}':
+			d.pos++
+			return nil
+		default:
+			return d.error(c, "after object key:value pair")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package djson
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOnly(t *testing.T) {
+	expected := map[string]interface{}{
+		"string_2": "Déjà vu",
+		"array_5": []interface{}{
+			map[string]interface{}{},
+			map[string]interface{}{},
+			"d", "d", "s", "a", 3.0,
+			map[string]interface{}{
+				"array_5_1": []interface{}{
+					map[string]interface{}{
+						"array_5_1_1": []interface{}{"a", "b", "c", "d"},
+					},
+				},
+			},
+		},
+		"object_4": map[string]interface{}{"a": 1.0, "c": map[string]interface{}{"d": "d", "f": 2.0}},
+		"object_6": map[string]interface{}{
+			"c": map[string]interface{}{"cb": "a"},
+		},
+	}
+	paths := []string{"string_2", "array_5.array_5_1.array_5_1_1", "object_4.c", "object_4", "object_6.c.cb", "missing", "object_6.missing"}
+	for _, setup := range []func(*Decoder){
+		func(*Decoder) {},
+		(*Decoder).AllocString,
+		(*Decoder).Arena,
+		(*Decoder).JSON5,
+		func(d *Decoder) { d.InternKeys(100) },
+	} {
+		d := NewDecoder(allValueIndent)
+		setup(d)
+		d.Only(paths...)
+		val, err := d.DecodeObject()
+		if err != nil {
+			t.Fatalf("DecodeObject: unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(val, expected) {
+			t.Errorf("DecodeObject = %v; want %v", val, expected)
+		}
+	}
+
+	// the projection is disabled without arguments.
+	d := NewDecoder(allValueIndent)
+	d.Only("string_2")
+	d.Only()
+	expected, _ = DecodeObject(allValueIndent)
+	if val, err := d.DecodeObject(); err != nil || !reflect.DeepEqual(val, expected) {
+		t.Errorf("DecodeObject = %v, %v; want the whole object", val, err)
+	}
+}
+
+func TestOnlyErrors(t *testing.T) {
+	var inputs []string
+	for _, tt := range decodeTests {
+		inputs = append(inputs, tt.in, `{"a": 1, "skip": `+tt.in+`}`)
+	}
+	files, _ := filepath.Glob("testdata/JSONTestSuite/test_parsing/*.json")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, string(data), `{"a": 1, "skip": `+string(data)+`}`)
+	}
+	for _, setup := range []func(*Decoder){
+		func(*Decoder) {},
+		(*Decoder).AllowComments,
+		(*Decoder).AllowTrailingCommas,
+		(*Decoder).AllowNaN,
+		(*Decoder).JSON5,
+		func(d *Decoder) { d.InvalidUTF8(UTF8Reject) },
+	} {
+		for _, in := range inputs {
+			d := NewDecoder([]byte(in))
+			setup(d)
+			_, expected := d.Decode()
+			d.Reset([]byte(in))
+			d.Only("a", "b.c")
+			if _, err := d.Decode(); !reflect.DeepEqual(err, expected) {
+				t.Errorf("Decode(%.40q): expecting the same error as without Only\n\tactual: %v\n\twant: %v", in, err, expected)
+			}
+		}
+	}
+
+	// the lenient mode reports the same errors, and drops the same members.
+	for _, in := range []string{`{"a": [1, x, 2], "b": {"c": 1, "d": [}, "e": 3}`, `{"x": [1 2], "a": 1}`} {
+		expected, errs := NewDecoder([]byte(in)).DecodeLenient()
+		d := NewDecoder([]byte(in))
+		d.Only("a", "b.c")
+		val, errs1 := d.DecodeLenient()
+		if !reflect.DeepEqual(errs, errs1) {
+			t.Errorf("DecodeLenient(%q): expecting the same errors\n\tactual: %v\n\twant: %v", in, errs1, errs)
+		}
+		if obj := expected.(map[string]interface{}); obj["b"] != nil {
+			obj["b"] = map[string]interface{}{"c": obj["b"].(map[string]interface{})["c"]}
+			delete(obj, "e")
+		}
+		delete(expected.(map[string]interface{}), "x")
+		if !reflect.DeepEqual(val, expected) {
+			t.Errorf("DecodeLenient(%q) = %v; want %v", in, val, expected)
+		}
+	}
+}
+
+func TestOnlyAllocs(t *testing.T) {
+	small := `{"APP_ID": "BD311", "IP": "69.89.31.226", "Date": 1475332371532`
+	var b strings.Builder
+	b.WriteString(small)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, `, "field_%d": {"name": "value %d", "tags": ["a\n", "b", -%d.5], "ok": true}`, i, i, i)
+	}
+	b.WriteString("}")
+	allocs := func(data []byte, paths ...string) float64 {
+		d := NewDecoder(data)
+		d.Only(paths...)
+		return testing.AllocsPerRun(100, func() {
+			d.Reset(data)
+			if _, err := d.DecodeObject(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	// the skipped members are not allocated.
+	want := allocs([]byte(small + "}"))
+	if n := allocs([]byte(b.String()), "APP_ID", "IP", "Date"); n > want {
+		t.Errorf("DecodeObject with Only allocates %v times; want at most %v", n, want)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package djson
+
+// raceEnabled reports whether the tests run with the race detector, which
+// makes sync.Pool drop items at random, so pooled decoders are allocated.
+const raceEnabled = true
@@ -0,0 +1,71 @@
+package djson
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The functions in this file scan the input 8 bytes at a time, using SWAR
+// (SIMD within a register) arithmetic on uint64 words. Each function builds
+// a mask that has the high bit set in every byte that matches, without
+// carries between the bytes, so the first match is found by counting the
+// trailing zeros of the mask(the words are loaded in little-endian order).
+
+const (
+	lsb = 0x0101010101010101
+	msb = 0x8080808080808080
+)
+
+// eqMask returns a mask of the bytes in w that are equal to c.
+func eqMask(w uint64, c byte) uint64 {
+	return ^neMask(w, c) & msb
+}
+
+// neMask returns a word that has the high bit set in every byte of w that is
+// not equal to c. The other bits are undefined, so the masks of several
+// characters can be combined with AND before they are inverted.
+func neMask(w uint64, c byte) uint64 {
+	x := w ^ (lsb * uint64(c))
+	return ((x &^ msb) + (msb - lsb)) | x
+}
+
+// plainString returns the number of bytes at the beginning of b that do not
+// need special handling in a string literal. That is, bytes that are not
+// '"', '\', control characters or non-ASCII.
+func plainString(b []byte) int {
+	i := 0
+	for ; i+8 <= len(b); i += 8 {
+		w := binary.LittleEndian.Uint64(b[i:])
+		// bytes that are below 0x20 or above 0x7f.
+		m := (^((w &^ msb) + lsb*(0x80-0x20)) | w) & msb
+		m |= eqMask(w, '"') | eqMask(w, '\\')
+		if m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(b); i++ {
+		if c := b[i]; c == '"' || c == '\\' || c < 0x20 || c >= 0x80 {
+			break
+		}
+	}
+	return i
+}
+
+// whitespace returns the number of JSON white spaces(' ', '\t', '\n' and
+// '\r') at the beginning of b.
+func whitespace(b []byte) int {
+	i := 0
+	for ; i+8 <= len(b); i += 8 {
+		w := binary.LittleEndian.Uint64(b[i:])
+		m := eqMask(w, ' ') | eqMask(w, '\t') | eqMask(w, '\n') | eqMask(w, '\r')
+		if m != msb {
+			return i + bits.TrailingZeros64(^m&msb)/8
+		}
+	}
+	for ; i < len(b); i++ {
+		if c := b[i]; c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			break
+		}
+	}
+	return i
+}
@@ -0,0 +1,46 @@
+package djson
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSWAR(t *testing.T) {
+	plain := func(b []byte) int {
+		for i, c := range b {
+			if c == '"' || c == '\\' || c < 0x20 || c >= 0x80 {
+				return i
+			}
+		}
+		return len(b)
+	}
+	spaces := func(b []byte) int {
+		for i, c := range b {
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				return i
+			}
+		}
+		return len(b)
+	}
+	// bytes that are close to the boundaries of the masks.
+	alphabet := []byte{' ', '\t', '\n', '\r', '"', '\\', 'a', '0', 0x00, 0x1f, 0x21, 0x5b, 0x5d, 0x7f, 0x80, 0x9f, 0xa0, 0xdc, 0xff}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		b := make([]byte, rnd.Intn(40))
+		for j := range b {
+			if rnd.Intn(4) == 0 {
+				b[j] = alphabet[rnd.Intn(len(alphabet))]
+			} else if i%2 == 0 {
+				b[j] = "abc 123"[rnd.Intn(7)]
+			} else {
+				b[j] = " \t\n\r"[rnd.Intn(4)]
+			}
+		}
+		if n, want := plainString(b), plain(b); n != want {
+			t.Fatalf("plainString(%q) = %d; want %d", b, n, want)
+		}
+		if n, want := whitespace(b), spaces(b); n != want {
+			t.Fatalf("whitespace(%q) = %d; want %d", b, n, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package djson
+
+import "math"
+
+// Value wraps a decoded value(e.g. the result of Decode), and provides typed
+// accessors for it, instead of type assertions.
+//
+//	val, err := djson.Decode(data)
+//	if err != nil {
+//		return err
+//	}
+//	v := djson.ValueOf(val)
+//	name, ok := v.Get("person", "name").String()
+//	followers := v.Get("person", "github", "followers").IntOr(0)
+//	first := v.Get("tags", "[0]").MustString()
+//
+// The zero Value represents a missing value; It is returned by Get for paths
+// that do not exist, and its accessors report false. Note that a JSON null
+// exists, and its type is Null.
+type Value struct {
+	v      interface{}
+	exists bool
+}
+
+// ValueOf returns a Value that wraps the given decoded value.
+func ValueOf(v interface{}) Value {
+	return Value{v, true}
+}
+
+// Interface returns the wrapped value, or nil if it does not exist.
+func (v Value) Interface() interface{} {
+	return v.v
+}
+
+// Exists reports whether the value exists.
+func (v Value) Exists() bool {
+	return v.exists
+}
+
+// Type returns the JSON type of the value, or Unknown if it does not exist.
+func (v Value) Type() ValueType {
+	if !v.exists {
+		return Unknown
+	}
+	return Type(v.v)
+}
+
+// Get returns the value in the given path. A path is a list of object keys
+// and array indexes in brackets(e.g. "users", "[0]", "name"), like in
+// EachPath. It returns the zero Value if the path does not exist.
+func (v Value) Get(path ...string) Value {
+	for _, k := range path {
+		switch val := v.v.(type) {
+		case map[string]interface{}:
+			elem, ok := val[k]
+			if !ok {
+				return Value{}
+			}
+			v.v = elem
+		case []interface{}:
+			i, ok := arrayIndex(k)
+			if !ok || i >= len(val) {
+				return Value{}
+			}
+			v.v = val[i]
+		default:
+			return Value{}
+		}
+	}
+	return v
+}
+
+// String returns the value if it is a string, and reports whether it is.
+func (v Value) String() (string, bool) {
+	if v.Type() != String {
+		return "", false
+	}
+	return v.v.(string), true
+}
+
+// Float returns the value if it is a number, and reports whether it is.
+func (v Value) Float() (float64, bool) {
+	if v.Type() != Number {
+		return 0, false
+	}
+	return v.v.(float64), true
+}
+
+// Int returns the value if it is a number that is an integer in the int64
+// range, and reports whether it is.
+func (v Value) Int() (int64, bool) {
+	f, ok := v.Float()
+	if !ok || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// Bool returns the value if it is a boolean, and reports whether it is.
+func (v Value) Bool() (bool, bool) {
+	if v.Type() != Bool {
+		return false, false
+	}
+	return v.v.(bool), true
+}
+
+// Array returns the value if it is an array, and reports whether it is.
+func (v Value) Array() ([]interface{}, bool) {
+	if v.Type() != Array {
+		return nil, false
+	}
+	return v.v.([]interface{}), true
+}
+
+// Object returns the value if it is an object, and reports whether it is.
+func (v Value) Object() (map[string]interface{}, bool) {
+	if v.Type() != Object {
+		return nil, false
+	}
+	return v.v.(map[string]interface{}), true
+}
+
+// IsNull reports whether the value is a JSON null.
+func (v Value) IsNull() bool {
+	return v.Type() == Null
+}
+
+// StringOr returns the value if it is a string, or def otherwise.
+func (v Value) StringOr(def string) string {
+	if s, ok := v.String(); ok {
+		return s
+	}
+	return def
+}
+
+// FloatOr returns the value if it is a number, or def otherwise.
+func (v Value) FloatOr(def float64) float64 {
+	if f, ok := v.Float(); ok {
+		return f
+	}
+	return def
+}
+
+// IntOr returns the value if it is an integer, or def otherwise.
+func (v Value) IntOr(def int64) int64 {
+	if i, ok := v.Int(); ok {
+		return i
+	}
+	return def
+}
+
+// BoolOr returns the value if it is a boolean, or def otherwise.
+func (v Value) BoolOr(def bool) bool {
+	if b, ok := v.Bool(); ok {
+		return b
+	}
+	return def
+}
+
+// MustString is like String, but it panics if the value is not a string.
+func (v Value) MustString() string {
+	s, ok := v.String()
+	if !ok {
+		v.typeError("string")
+	}
+	return s
+}
+
+// MustFloat is like Float, but it panics if the value is not a number.
+func (v Value) MustFloat() float64 {
+	f, ok := v.Float()
+	if !ok {
+		v.typeError("number")
+	}
+	return f
+}
+
+// MustInt is like Int, but it panics if the value is not an integer.
+func (v Value) MustInt() int64 {
+	i, ok := v.Int()
+	if !ok {
+		v.typeError("integer")
+	}
+	return i
+}
+
+// MustBool is like Bool, but it panics if the value is not a boolean.
+func (v Value) MustBool() bool {
+	b, ok := v.Bool()
+	if !ok {
+		v.typeError("boolean")
+	}
+	return b
+}
+
+// MustArray is like Array, but it panics if the value is not an array.
+func (v Value) MustArray() []interface{} {
+	a, ok := v.Array()
+	if !ok {
+		v.typeError("array")
+	}
+	return a
+}
+
+// MustObject is like Object, but it panics if the value is not an object.
+func (v Value) MustObject() map[string]interface{} {
+	o, ok := v.Object()
+	if !ok {
+		v.typeError("object")
+	}
+	return o
+}
+
+// typeError is called by the Must methods when the value is not of the
+// expected type.
+func (v Value) typeError(want string) {
+	if !v.exists {
+		panic("djson: missing value, expecting " + want)
+	}
+	panic("djson: " + v.Type().String() + " value, expecting " + want)
+}
@@ -0,0 +1,110 @@
+package djson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestValue(t *testing.T) {
+	val, err := Decode(allValueIndent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := ValueOf(val)
+
+	if s, ok := v.Get("string_2").String(); !ok || s != "Déjà vu" {
+		t.Errorf(`String = %q, %v; want "Déjà vu", true`, s, ok)
+	}
+	if f, ok := v.Get("float_1").Float(); !ok || f != 3.1415926 {
+		t.Errorf("Float = %v, %v; want 3.1415926, true", f, ok)
+	}
+	if i, ok := v.Get("int_2").Int(); !ok || i != -1 {
+		t.Errorf("Int = %v, %v; want -1, true", i, ok)
+	}
+	if b, ok := v.Get("object_6", "c", "cc", "cca", "[6]").Bool(); !ok || !b {
+		t.Errorf("Bool = %v, %v; want true, true", b, ok)
+	}
+	if a, ok := v.Get("array_2").Array(); !ok || !reflect.DeepEqual(a, []interface{}{2.0, 3.0, 4.0, 4.0}) {
+		t.Errorf("Array = %v, %v; want [2 3 4 4], true", a, ok)
+	}
+	if o, ok := v.Get("object_3").Object(); !ok || !reflect.DeepEqual(o, map[string]interface{}{"a": 1.0, "b": 3.0}) {
+		t.Errorf("Object = %v, %v; want map[a:1 b:3], true", o, ok)
+	}
+	if v.Get().Interface() == nil || !reflect.DeepEqual(v.Get().Interface(), val) {
+		t.Error("expecting an empty path to return the value itself")
+	}
+
+	// types, null and missing values.
+	for _, tt := range []struct {
+		v      Value
+		typ    ValueType
+		exists bool
+		null   bool
+	}{
+		{v, Object, true, false},
+		{v.Get("null_1"), Null, true, true},
+		{v.Get("array_4", "[1]", "[0]"), String, true, false},
+		{v.Get("missing"), Unknown, false, false},
+		{v.Get("array_2", "[4]"), Unknown, false, false},
+		{v.Get("array_2", "0"), Unknown, false, false},
+		{v.Get("string_1", "a"), Unknown, false, false},
+		{v.Get("null_1", "a"), Unknown, false, false},
+		{Value{}, Unknown, false, false},
+	} {
+		if typ := tt.v.Type(); typ != tt.typ || tt.v.Exists() != tt.exists || tt.v.IsNull() != tt.null {
+			t.Errorf("%v: type = %v, exists = %v, null = %v; want %v, %v, %v", tt.v.Interface(), typ, tt.v.Exists(), tt.v.IsNull(), tt.typ, tt.exists, tt.null)
+		}
+	}
+
+	// integers.
+	for _, tt := range []struct {
+		f  float64
+		ok bool
+	}{
+		{0, true}, {-5, true}, {1 << 53, true}, {math.MinInt64, true},
+		{1.5, false}, {math.MaxInt64, false}, {math.Inf(1), false}, {math.NaN(), false},
+	} {
+		if i, ok := ValueOf(tt.f).Int(); ok != tt.ok || ok && float64(i) != tt.f {
+			t.Errorf("Int(%v) = %v, %v; want ok = %v", tt.f, i, ok, tt.ok)
+		}
+	}
+
+	// defaults.
+	missing := v.Get("missing")
+	if missing.StringOr("a") != "a" || missing.FloatOr(1.5) != 1.5 || missing.IntOr(7) != 7 || !missing.BoolOr(true) {
+		t.Error("expecting the defaults of a missing value")
+	}
+	if v.Get("string_1").IntOr(7) != 7 || v.Get("float_1").IntOr(7) != 7 || v.Get("int_1").IntOr(7) != 42 {
+		t.Error("expecting the defaults of values of other types")
+	}
+	if v.Get("string_4").StringOr("a") != "null" || v.Get("float_3").FloatOr(1) != 0.1415926 || v.Get("bool_1").BoolOr(true) {
+		t.Error("expecting the values instead of the defaults")
+	}
+
+	// Must variants.
+	if v.Get("string_5").MustString() != "5" || v.Get("int_3").MustInt() != 11111111 || v.Get("int_1").MustFloat() != 42 ||
+		!v.Get("bool_2").MustBool() || len(v.Get("array_3").MustArray()) != 6 || len(v.Get("object_4").MustObject()) != 2 {
+		t.Error("unexpected values of the Must methods")
+	}
+	for _, tt := range []struct {
+		fn  func()
+		msg string
+	}{
+		{func() { v.Get("int_1").MustString() }, "djson: number value, expecting string"},
+		{func() { v.Get("float_1").MustInt() }, "djson: number value, expecting integer"},
+		{func() { v.Get("null_1").MustBool() }, "djson: null value, expecting boolean"},
+		{func() { v.MustArray() }, "djson: object value, expecting array"},
+		{func() { v.Get("array_1").MustObject() }, "djson: array value, expecting object"},
+		{func() { v.Get("missing").MustFloat() }, "djson: missing value, expecting number"},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != tt.msg {
+					t.Errorf("panic = %v; want %q", r, tt.msg)
+				}
+			}()
+			tt.fn()
+		}()
+	}
+}
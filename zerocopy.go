@@ -0,0 +1,11 @@
+//go:build !purego && go1.20
+// +build !purego,go1.20
+
+package djson
+
+import "unsafe"
+
+// unsafeString returns a string that shares its memory with b.
+func unsafeString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
@@ -0,0 +1,11 @@
+//go:build purego || !go1.20
+// +build purego !go1.20
+
+package djson
+
+// unsafeString returns a copy of b when the "unsafe" package is not
+// allowed, or when the toolchain does not provide unsafe.String(Go 1.20).
+// In this case, ZeroCopy behaves like AllocString.
+func unsafeString(b []byte) string {
+	return string(b)
+}
@@ -0,0 +1,75 @@
+//go:build !purego && go1.20
+// +build !purego,go1.20
+
+package djson
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestZeroCopy(t *testing.T) {
+	data := []byte(`{"a": ["b", "c\n", "d"], "e": 1.5}`)
+	expected, _ := Decode(data)
+	d := NewDecoder(data)
+	d.ZeroCopy()
+	out, err := d.DecodeObject()
+	if err != nil {
+		t.Fatalf("expecting decode not to fail: %q", err)
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("compare to Decode \n\tactual: %v\n\twant: %v", out, expected)
+	}
+
+	// unescaped strings are views into the data, and escaped ones are not.
+	aliased := func(s string) bool {
+		p := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+		start := uintptr(unsafe.Pointer(&data[0]))
+		return p >= start && p < start+uintptr(len(data))
+	}
+	for k := range out {
+		if !aliased(k) {
+			t.Errorf("expecting key %q to alias the data", k)
+		}
+	}
+	arr := out["a"].([]interface{})
+	if !aliased(arr[0].(string)) || !aliased(arr[2].(string)) {
+		t.Error("expecting unescaped strings to alias the data")
+	}
+	if aliased(arr[1].(string)) {
+		t.Error("expecting escaped string to be allocated")
+	}
+
+	// the strings change with the data.
+	data[8] = 'x'
+	if arr[0] != "x" {
+		t.Errorf("%v, want x", arr[0])
+	}
+
+	// valid non-ASCII strings are views into the data as well, and invalid
+	// ones are replaced.
+	data = []byte(`["Déjà vu", "bad ` + "\xff" + `", {"ключ": "\u00e9"}]`)
+	d.Reset(data)
+	v, err := d.Decode()
+	if expected, _ := Decode(data); err != nil || !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%v, %v; want %v", v, err, expected)
+	}
+	arr = v.([]interface{})
+	if !aliased(arr[0].(string)) || aliased(arr[1].(string)) {
+		t.Error("expecting only the valid UTF-8 string to alias the data")
+	}
+	for k, v := range arr[2].(map[string]interface{}) {
+		if !aliased(k) || aliased(v.(string)) {
+			t.Error("expecting the key to alias the data, and the escaped value not to")
+		}
+	}
+
+	// Reset references the new data.
+	data = []byte(`"foo"`)
+	d.Reset(data)
+	v, err = d.Decode()
+	if err != nil || v != "foo" || !aliased(v.(string)) {
+		t.Errorf("%v, %v; want an aliased foo", v, err)
+	}
+}